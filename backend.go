@@ -0,0 +1,191 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Request is a single completion request passed to a Backend, independent
+// of which provider ends up serving it. Temperature is a pointer so an
+// explicit 0 is distinguishable from "unset"; see
+// SummarizeOptions.Temperature.
+type Request struct {
+	Prompt          string
+	Model           string
+	Temperature     *float32
+	MaxOutputTokens int
+}
+
+// Response is a Backend's completion result.
+type Response struct {
+	Content string
+}
+
+// Backend is a single LLM completion provider a Router can route requests
+// to. AzureBackend, OpenAIBackend, and GeminiBackend are the built-in
+// implementations; callers can add their own to route to other providers.
+type Backend interface {
+	Name() string
+	Complete(ctx context.Context, req Request) (Response, error)
+	Healthy() bool
+}
+
+// StreamingBackend is implemented by a Backend that can deliver a completion
+// incrementally instead of all at once. AzureBackend, OpenAIBackend, and
+// GeminiBackend all implement it; Router.CompleteStream only routes to
+// entries whose Backend satisfies it, so a custom Backend that doesn't is
+// simply skipped rather than causing an error.
+type StreamingBackend interface {
+	Backend
+	CompleteStream(ctx context.Context, req Request, onDelta func(string)) error
+}
+
+// AzureBackend serves completions from an Azure OpenAI deployment.
+type AzureBackend struct {
+	client openai.Client
+}
+
+func NewAzureBackend(client openai.Client) *AzureBackend {
+	return &AzureBackend{client: client}
+}
+
+func (b *AzureBackend) Name() string { return "azure" }
+
+func (b *AzureBackend) Healthy() bool { return true }
+
+func (b *AzureBackend) Complete(ctx context.Context, req Request) (Response, error) {
+	resp, err := b.client.CreateChatCompletion(ctx, chatCompletionRequest(req))
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{Content: resp.Choices[0].Message.Content}, nil
+}
+
+func (b *AzureBackend) CompleteStream(ctx context.Context, req Request, onDelta func(string)) error {
+	return completeChatStream(ctx, &b.client, req, onDelta)
+}
+
+// OpenAIBackend serves completions from the public OpenAI API.
+type OpenAIBackend struct {
+	client openai.Client
+}
+
+func NewOpenAIBackend(client openai.Client) *OpenAIBackend {
+	return &OpenAIBackend{client: client}
+}
+
+func (b *OpenAIBackend) Name() string { return "openai" }
+
+func (b *OpenAIBackend) Healthy() bool { return true }
+
+func (b *OpenAIBackend) Complete(ctx context.Context, req Request) (Response, error) {
+	resp, err := b.client.CreateChatCompletion(ctx, chatCompletionRequest(req))
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{Content: resp.Choices[0].Message.Content}, nil
+}
+
+func (b *OpenAIBackend) CompleteStream(ctx context.Context, req Request, onDelta func(string)) error {
+	return completeChatStream(ctx, &b.client, req, onDelta)
+}
+
+// openAIZeroTemperatureEpsilon stands in for an explicit Temperature of 0
+// when talking to OpenAI/Azure. go-openai's ChatCompletionRequest.Temperature
+// is tagged `omitempty`, so serializing a literal 0 drops the field and the
+// provider substitutes its own (non-zero) default instead of sampling
+// deterministically as the caller asked. This epsilon is far too small to
+// affect sampling while still surviving omitempty.
+const openAIZeroTemperatureEpsilon = 1e-7
+
+func chatCompletionRequest(req Request) openai.ChatCompletionRequest {
+	model := req.Model
+	if model == "" {
+		model = openai.GPT3Dot5Turbo16K
+	}
+	request := openai.ChatCompletionRequest{
+		Model: model,
+		Messages: []openai.ChatCompletionMessage{{
+			Role:    openai.ChatMessageRoleUser,
+			Content: req.Prompt,
+		}},
+		MaxTokens: req.MaxOutputTokens,
+	}
+	if req.Temperature != nil {
+		request.Temperature = *req.Temperature
+		if request.Temperature == 0 {
+			request.Temperature = openAIZeroTemperatureEpsilon
+		}
+	}
+	return request
+}
+
+// completeChatStream drives an OpenAI-compatible streaming chat completion
+// (shared by AzureBackend and OpenAIBackend, which differ only in which
+// endpoint client is configured for), calling onDelta with each non-empty
+// content delta as it arrives.
+func completeChatStream(ctx context.Context, client *openai.Client, req Request, onDelta func(string)) error {
+	chatReq := chatCompletionRequest(req)
+	chatReq.Stream = true
+	stream, err := client.CreateChatCompletionStream(ctx, chatReq)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(resp.Choices) == 0 {
+			continue
+		}
+		delta := resp.Choices[0].Delta.Content
+		if delta == "" {
+			continue
+		}
+		onDelta(delta)
+	}
+}
+
+// GeminiBackend serves completions from the Gemini generateContent API.
+type GeminiBackend struct {
+	token string
+}
+
+func NewGeminiBackend(token string) *GeminiBackend {
+	return &GeminiBackend{token: token}
+}
+
+func (b *GeminiBackend) Name() string { return "gemini" }
+
+func (b *GeminiBackend) Healthy() bool { return true }
+
+func (b *GeminiBackend) Complete(ctx context.Context, req Request) (Response, error) {
+	opts := SummarizeOptions{
+		Model:           req.Model,
+		Temperature:     req.Temperature,
+		MaxOutputTokens: req.MaxOutputTokens,
+	}
+	content, err := doRequestGeminiWithContext(ctx, b.token, req.Prompt, opts)
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{Content: *content}, nil
+}
+
+func (b *GeminiBackend) CompleteStream(ctx context.Context, req Request, onDelta func(string)) error {
+	opts := SummarizeOptions{
+		Model:           req.Model,
+		Temperature:     req.Temperature,
+		MaxOutputTokens: req.MaxOutputTokens,
+	}
+	return doRequestGeminiStreamWithContext(ctx, b.token, req.Prompt, opts, onDelta)
+}
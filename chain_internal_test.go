@@ -0,0 +1,278 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// chainFakeBackend is a minimal Backend fake whose Complete behavior is
+// fully controlled by the test, so the chain functions' calling and caching
+// behavior can be exercised without a real provider.
+type chainFakeBackend struct {
+	mu    sync.Mutex
+	calls int
+	fn    func(req Request) (Response, error)
+}
+
+func (b *chainFakeBackend) Name() string  { return "fake" }
+func (b *chainFakeBackend) Healthy() bool { return true }
+func (b *chainFakeBackend) Complete(ctx context.Context, req Request) (Response, error) {
+	b.mu.Lock()
+	b.calls++
+	b.mu.Unlock()
+	return b.fn(req)
+}
+
+// mapCache is an in-memory Cache fake so tests can inspect exactly which
+// keys a chain function wrote.
+type mapCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{data: map[string]string{}}
+}
+
+func (c *mapCache) Get(key string) *string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.data[key]; ok {
+		return &v
+	}
+	return nil
+}
+
+func (c *mapCache) Set(key string, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+}
+
+func TestBuildRefinePromptFirstWindow(t *testing.T) {
+	got := buildRefinePrompt("Write a TL;DR: ", "", "the new material")
+	want := "Write a TL;DR: the new material"
+	if got != want {
+		t.Errorf("buildRefinePrompt() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildRefinePromptSubsequentWindow(t *testing.T) {
+	got := buildRefinePrompt("Write a TL;DR: ", "summary so far", "more material")
+	if !strings.Contains(got, "summary so far") || !strings.Contains(got, "more material") {
+		t.Errorf("buildRefinePrompt() = %q, want it to reference both the running summary and the new material", got)
+	}
+}
+
+// TestSummarizeMapReduceBoundedConcurrency needs a real tiktoken encoding
+// because summarizeMapReduce's reduce pass always recurses through
+// summarize(), which counts prompt tokens via the Client's tiktoken
+// encoding regardless of opts.Model; it skips rather than fails offline.
+func TestSummarizeMapReduceBoundedConcurrency(t *testing.T) {
+	tk, err := tiktoken.EncodingForModel("gpt-3.5-turbo")
+	if err != nil {
+		t.Skipf("tiktoken encoding unavailable in this environment: %s", err)
+	}
+
+	const concurrency = 2
+	const numWindows = 6
+
+	started := make(chan struct{}, numWindows+1)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+
+	backend := &chainFakeBackend{fn: func(req Request) (Response, error) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+		started <- struct{}{}
+		<-release
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return Response{Content: "partial:" + req.Prompt}, nil
+	}}
+
+	client := &Client{tiktoken: *tk, cache: newMapCache(), logger: NoOpLogger{}}
+	client.router = NewRouter(nil, NoOpLogger{})
+	client.router.AddBackend(backend, AccessConfig{})
+
+	windows := make([]string, numWindows)
+	for i := range windows {
+		windows[i] = fmt.Sprintf("window %d", i)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.summarizeMapReduce(context.Background(), windows, SummarizeOptions{Strategy: CaseBriefStrategy{}, Concurrency: concurrency})
+		errCh <- err
+	}()
+
+	for i := 0; i < concurrency; i++ {
+		<-started
+	}
+	select {
+	case <-started:
+		t.Fatal("a window started before an earlier one released its semaphore slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	mu.Lock()
+	if maxActive > concurrency {
+		t.Errorf("maxActive = %d, want at most %d", maxActive, concurrency)
+	}
+	mu.Unlock()
+
+	close(release)
+	for i := concurrency; i < numWindows; i++ {
+		<-started
+	}
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("summarizeMapReduce() error = %v", err)
+	}
+}
+
+// TestSummarizeMapReduceFailFastCancelsAndCachesPartial also needs a real
+// tiktoken encoding, for the same reason as above.
+func TestSummarizeMapReduceFailFastCancelsAndCachesPartial(t *testing.T) {
+	tk, err := tiktoken.EncodingForModel("gpt-3.5-turbo")
+	if err != nil {
+		t.Skipf("tiktoken encoding unavailable in this environment: %s", err)
+	}
+
+	cache := newMapCache()
+	const numWindows = 4
+	windows := make([]string, numWindows)
+	for i := range windows {
+		windows[i] = fmt.Sprintf("window %d", i)
+	}
+
+	backend := &chainFakeBackend{fn: func(req Request) (Response, error) {
+		if req.Prompt == windows[1] {
+			return Response{}, errors.New("simulated backend failure")
+		}
+		return Response{Content: "summary-of-" + req.Prompt}, nil
+	}}
+
+	client := &Client{tiktoken: *tk, cache: cache, logger: NoOpLogger{}}
+	client.router = NewRouter(nil, NoOpLogger{})
+	client.router.AddBackend(backend, AccessConfig{})
+
+	// Concurrency 1 makes the windows run strictly in order, so the failure
+	// on windows[1] deterministically leaves windows[2] and windows[3]
+	// never started.
+	_, err = client.summarizeMapReduce(context.Background(), windows, SummarizeOptions{Strategy: CaseBriefStrategy{}, Concurrency: 1})
+	if err == nil {
+		t.Fatal("summarizeMapReduce() error = nil, want the simulated backend failure")
+	}
+
+	if cached := cache.Get("gpt:" + GetMD5Hash(windows[0])); cached == nil {
+		t.Error("expected windows[0], which completed before the failure, to be cached")
+	}
+	if cached := cache.Get("gpt:" + GetMD5Hash(windows[1])); cached != nil {
+		t.Error("the failing window should not have cached a result")
+	}
+	if cached := cache.Get("gpt:" + GetMD5Hash(windows[2])); cached != nil {
+		t.Error("a window never started after the cancel should not have cached a result")
+	}
+}
+
+func TestSummarizeRefineAccumulatesSequentially(t *testing.T) {
+	var seen []string
+	backend := &chainFakeBackend{fn: func(req Request) (Response, error) {
+		seen = append(seen, req.Prompt)
+		return Response{Content: fmt.Sprintf("refined:%d", len(seen))}, nil
+	}}
+
+	client := &Client{cache: newMapCache(), logger: NoOpLogger{}}
+	client.router = NewRouter(nil, NoOpLogger{})
+	client.router.AddBackend(backend, AccessConfig{})
+
+	prompt := "Summarize: "
+	windows := []string{prompt + "first chunk", prompt + "second chunk", prompt + "third chunk"}
+
+	result, err := client.summarizeRefine(context.Background(), windows, prompt, SummarizeOptions{Strategy: CaseBriefStrategy{}})
+	if err != nil {
+		t.Fatalf("summarizeRefine() error = %v", err)
+	}
+	if *result != "refined:3" {
+		t.Errorf("summarizeRefine() = %q, want the last refine call's result", *result)
+	}
+	if len(seen) != 3 {
+		t.Fatalf("backend called %d times, want 3 (one per window)", len(seen))
+	}
+	if strings.Contains(seen[0], "refined:") {
+		t.Errorf("first refine call = %q, should have no running summary yet", seen[0])
+	}
+	if !strings.Contains(seen[1], "refined:1") {
+		t.Errorf("second refine call = %q, want it to reference the first call's running summary", seen[1])
+	}
+	if !strings.Contains(seen[2], "refined:2") {
+		t.Errorf("third refine call = %q, want it to reference the second call's running summary", seen[2])
+	}
+}
+
+// TestSummarizeDispatchesByChain guards the opts.Chain switch in summarize():
+// ChainRefine should never recurse back through summarize() for a reduce
+// pass, while the default (ChainRecursive) and ChainMapReduce both do. It
+// needs a real tiktoken encoding for the same reason the gate tests do, so
+// it skips rather than fails offline.
+func TestSummarizeDispatchesByChain(t *testing.T) {
+	tk, err := tiktoken.EncodingForModel("gpt-3.5-turbo")
+	if err != nil {
+		t.Skipf("tiktoken encoding unavailable in this environment: %s", err)
+	}
+
+	text := strings.Repeat("测试文本用于触发窗口拆分。", 3000)
+
+	for _, tc := range []struct {
+		name  string
+		chain Chain
+	}{
+		{"recursive (default)", ChainRecursive},
+		{"map-reduce", ChainMapReduce},
+		{"refine", ChainRefine},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := &chainFakeBackend{fn: func(req Request) (Response, error) {
+				return Response{Content: "ok"}, nil
+			}}
+			client := &Client{tiktoken: *tk, cache: newMapCache(), logger: NoOpLogger{}}
+			client.router = NewRouter(nil, NoOpLogger{})
+			client.router.AddBackend(backend, AccessConfig{})
+
+			opts := SummarizeOptions{Strategy: CaseBriefStrategy{}, Model: "gemini-1.5-pro", Chain: tc.chain}
+			prompt := opts.Strategy.Build(text, opts.Language)
+			windowCount := len(client.splitIntoWindows(text, prompt, opts))
+			if windowCount <= 1 {
+				t.Fatalf("test fixture assumption broken: want multiple windows, got %d", windowCount)
+			}
+
+			if _, err := client.SummarizeWith(context.Background(), text, opts); err != nil {
+				t.Fatalf("SummarizeWith() error = %v", err)
+			}
+
+			want := windowCount
+			if tc.chain != ChainRefine {
+				// ChainRecursive and ChainMapReduce both feed their windows'
+				// partials back through summarize() for a final reduce pass.
+				want++
+			}
+			if backend.calls != want {
+				t.Errorf("backend.calls = %d, want %d", backend.calls, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package summarizer
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+var paragraphBreakMatcher = regexp.MustCompile(`\n[ \t]*\n`)
+
+// Chunk is one window produced by Chunker.Chunk, along with its token count
+// (excluding any prompt prefix): the sum of the counts of the units it was
+// packed from, which can differ slightly from counting Text as a whole.
+type Chunk struct {
+	Text   string
+	Tokens int
+}
+
+// ChunkOptions configures a Client.Chunk call.
+type ChunkOptions struct {
+	// Model selects the tokenizer: OpenAI model names resolve to their
+	// tiktoken encoding via tiktoken-go; anything else (e.g. "gemini-1.5-
+	// pro", "claude-3-opus") falls back to a character-based estimator.
+	// Empty defaults to "gpt-3.5-turbo", the encoding Summarize has always
+	// used.
+	Model string
+	// MaxTokens bounds each chunk, including PromptTokens. Defaults to
+	// 24000, the budget Summarize has always used for a single window.
+	MaxTokens int
+	// OverlapTokens repeats up to this many trailing tokens from the end of
+	// one chunk at the start of the next, so context spanning a chunk
+	// boundary isn't lost. Defaults to 0 (no overlap).
+	OverlapTokens int
+	// PromptTokens reserves room for an instruction prompt that will be
+	// prepended to each chunk, so a chunk plus its prompt never exceeds
+	// MaxTokens.
+	PromptTokens int
+}
+
+// Chunk splits text into token-bounded windows per opts, preferring splits
+// at paragraph, then sentence, then word boundaries so a window only ever
+// breaks mid-sentence or mid-word if a single sentence or word doesn't fit
+// in the budget on its own.
+func (c *Client) Chunk(text string, opts ChunkOptions) []Chunk {
+	model := opts.Model
+	if model == "" {
+		model = "gpt-3.5-turbo"
+	}
+	maxTokens := opts.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 24000
+	}
+	chunker := NewChunker(model, maxTokens, opts.OverlapTokens)
+	return chunker.Chunk(text, opts.PromptTokens)
+}
+
+// Chunker splits text into windows of at most maxTokens (as encoded for
+// model), each overlapping the previous by up to overlapTokens so callers
+// driving their own completion pipeline don't lose context across a
+// boundary.
+type Chunker struct {
+	model         string
+	maxTokens     int
+	overlapTokens int
+	countTokens   func(string) int
+}
+
+// NewChunker returns a Chunker for model. maxTokens and overlapTokens are
+// both in model's own token units, not characters or bytes.
+func NewChunker(model string, maxTokens int, overlapTokens int) *Chunker {
+	return &Chunker{
+		model:         model,
+		maxTokens:     maxTokens,
+		overlapTokens: overlapTokens,
+		countTokens:   tokenCounterForModel(model),
+	}
+}
+
+// fallbackTokenEstimators covers model families tiktoken-go has no
+// registered encoding for. Gemini and Claude both currently map to the same
+// character-based heuristic; they're kept as separate table entries since
+// their real tokenizers diverge and may warrant their own estimators later.
+var fallbackTokenEstimators = map[string]func(string) int{
+	"gemini": estimateTokensHeuristic,
+	"claude": estimateTokensHeuristic,
+}
+
+// tokenCounterForModel returns a function counting how many tokens model
+// would encode text as. It prefers an exact tiktoken-go encoding, falls
+// back to a per-family estimator for models tiktoken-go doesn't know
+// (Gemini, Claude), and otherwise falls back to GPT-4's cl100k_base
+// encoding as a reasonable general-purpose approximation.
+func tokenCounterForModel(model string) func(string) int {
+	if enc, err := tiktoken.EncodingForModel(model); err == nil {
+		return func(text string) int { return len(enc.Encode(text, nil, nil)) }
+	}
+	for prefix, estimate := range fallbackTokenEstimators {
+		if strings.HasPrefix(model, prefix) {
+			return estimate
+		}
+	}
+	if enc, err := tiktoken.GetEncoding("cl100k_base"); err == nil {
+		return func(text string) int { return len(enc.Encode(text, nil, nil)) }
+	}
+	return estimateTokensHeuristic
+}
+
+// estimateTokensHeuristic approximates a token count without a real
+// tokenizer: CJK runes are counted one-for-one, since they are usually
+// their own token, and everything else at roughly 4 characters per token.
+func estimateTokensHeuristic(text string) int {
+	var tokens, asciiRunes int
+	for _, r := range text {
+		if r >= 0x2E80 {
+			tokens++
+		} else {
+			asciiRunes++
+		}
+	}
+	return tokens + (asciiRunes+3)/4
+}
+
+// Chunk splits text into windows of at most c.maxTokens-promptTokens
+// tokens (never less than 1), preferring paragraph, then sentence, then
+// word boundaries, and repeating up to c.overlapTokens trailing tokens of
+// one window at the start of the next.
+func (c *Chunker) Chunk(text string, promptTokens int) []Chunk {
+	if text == "" {
+		return nil
+	}
+	budget := c.maxTokens - promptTokens
+	if budget < 1 {
+		budget = 1
+	}
+
+	units := c.splitUnits(text, budget)
+	unitTokens := make([]int, len(units))
+	for i, u := range units {
+		unitTokens[i] = c.countTokens(u)
+	}
+	return c.packUnits(units, unitTokens, budget)
+}
+
+// splitUnits breaks text into pieces that individually fit in budget tokens,
+// keeping a paragraph whole if it fits, otherwise splitting it into
+// sentences and, if a sentence
+// alone doesn't fit, sentences into words (and, as a last resort, an
+// overlong word into rune runs).
+func (c *Chunker) splitUnits(text string, budget int) []string {
+	var units []string
+	for _, paragraph := range splitParagraphs(text) {
+		if c.countTokens(paragraph) <= budget {
+			units = append(units, paragraph)
+			continue
+		}
+		for _, sentence := range splitSentences(paragraph) {
+			if c.countTokens(sentence) <= budget {
+				units = append(units, sentence)
+				continue
+			}
+			units = append(units, c.splitWords(sentence, budget)...)
+		}
+	}
+	return units
+}
+
+// splitWords packs sentence's words into pieces of at most budget tokens
+// each, falling back to splitting an individual overlong word (e.g. a long
+// CJK run with no spaces) into rune runs.
+func (c *Chunker) splitWords(sentence string, budget int) []string {
+	words := strings.Fields(sentence)
+	var out []string
+	var cur strings.Builder
+	var tokens int
+	flush := func() {
+		if cur.Len() > 0 {
+			out = append(out, cur.String())
+			cur.Reset()
+			tokens = 0
+		}
+	}
+	for _, w := range words {
+		wordTokens := c.countTokens(w)
+		if wordTokens > budget {
+			flush()
+			for _, piece := range c.splitRunes(w, budget) {
+				out = append(out, piece+" ")
+			}
+			continue
+		}
+		if tokens > 0 && tokens+wordTokens > budget {
+			flush()
+		}
+		cur.WriteString(w)
+		cur.WriteString(" ")
+		tokens += wordTokens
+	}
+	flush()
+	return out
+}
+
+// splitRunes is the last-resort splitter for a single word too large for
+// budget on its own. It binary-searches the longest rune-aligned prefix
+// that still fits, so the split is exact for whatever tokenizer c uses
+// rather than an arbitrary byte cut.
+func (c *Chunker) splitRunes(text string, budget int) []string {
+	if budget < 1 {
+		budget = 1
+	}
+	runes := []rune(text)
+	var out []string
+	for len(runes) > 0 {
+		lo, hi := 1, len(runes)
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if c.countTokens(string(runes[:mid])) <= budget {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		out = append(out, string(runes[:lo]))
+		runes = runes[lo:]
+	}
+	return out
+}
+
+// packUnits greedily packs units into windows of at most budget tokens,
+// starting each new window with the trailing units of the previous one
+// that fit within c.overlapTokens.
+func (c *Chunker) packUnits(units []string, unitTokens []int, budget int) []Chunk {
+	var chunks []Chunk
+	var windowUnits []string
+	var windowUnitTokens []int
+	var tokens int
+
+	flush := func() {
+		if len(windowUnits) == 0 {
+			return
+		}
+		chunks = append(chunks, Chunk{Text: strings.Join(windowUnits, ""), Tokens: tokens})
+	}
+
+	for i, u := range units {
+		ut := unitTokens[i]
+		if tokens > 0 && tokens+ut > budget {
+			flush()
+			windowUnits, windowUnitTokens, tokens = c.overlapTail(windowUnits, windowUnitTokens, budget, ut)
+		}
+		windowUnits = append(windowUnits, u)
+		windowUnitTokens = append(windowUnitTokens, ut)
+		tokens += ut
+	}
+	flush()
+	return chunks
+}
+
+// overlapTail returns the longest run of trailing units (and their token
+// sum) whose combined tokens fit within c.overlapTokens and leave room for
+// nextUnitTokens within budget, so the window overlapTail seeds never
+// overflows once the next unit is appended to it.
+func (c *Chunker) overlapTail(units []string, unitTokens []int, budget, nextUnitTokens int) ([]string, []int, int) {
+	if c.overlapTokens <= 0 || len(units) == 0 {
+		return nil, nil, 0
+	}
+	tailBudget := budget - nextUnitTokens
+	var tailUnits []string
+	var tailTokens []int
+	var tokens int
+	for i := len(units) - 1; i >= 0; i-- {
+		ut := unitTokens[i]
+		if tokens+ut > c.overlapTokens || tokens+ut > tailBudget {
+			break
+		}
+		tailUnits = append([]string{units[i]}, tailUnits...)
+		tailTokens = append([]int{ut}, tailTokens...)
+		tokens += ut
+	}
+	return tailUnits, tailTokens, tokens
+}
+
+// splitParagraphs splits text on blank lines, keeping each paragraph's
+// trailing blank-line separator attached so joining the results back with
+// strings.Join(paragraphs, "") reconstructs text exactly.
+func splitParagraphs(text string) []string {
+	indices := paragraphBreakMatcher.FindAllStringIndex(text, -1)
+	if len(indices) == 0 {
+		return []string{text}
+	}
+	var paragraphs []string
+	start := 0
+	for _, idx := range indices {
+		paragraphs = append(paragraphs, text[start:idx[1]])
+		start = idx[1]
+	}
+	paragraphs = append(paragraphs, text[start:])
+	return paragraphs
+}
+
+// commonAbbreviations lists trailing words whose period doesn't end a
+// sentence, so splitSentences doesn't break "Mr. Smith" or "e.g. this" in
+// two.
+var commonAbbreviations = map[string]bool{
+	"mr.": true, "mrs.": true, "ms.": true, "dr.": true, "prof.": true,
+	"sr.": true, "jr.": true, "vs.": true, "etc.": true, "e.g.": true,
+	"i.e.": true, "u.s.": true, "u.k.": true, "no.": true, "inc.": true,
+	"ltd.": true, "co.": true, "st.": true, "fig.": true,
+}
+
+func isSentenceTerminator(r rune) bool {
+	switch r {
+	case '.', '?', '!', '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+func isCJKTerminator(r rune) bool {
+	return r == '。' || r == '！' || r == '？'
+}
+
+// isAbbreviation reports whether sentenceSoFar ends in a known abbreviation
+// (e.g. "Dr.", "e.g."), in which case its trailing period doesn't end the
+// sentence.
+func isAbbreviation(sentenceSoFar string) bool {
+	fields := strings.Fields(sentenceSoFar)
+	if len(fields) == 0 {
+		return false
+	}
+	return commonAbbreviations[strings.ToLower(fields[len(fields)-1])]
+}
+
+// splitSentences splits text into sentences on '.', '?', '!' and their CJK
+// equivalents ('。', '！', '？'), treating a run of closing punctuation/
+// quotes after the terminator as part of the same sentence, skipping known
+// abbreviations, and requiring ASCII terminators (unlike CJK ones) to be
+// followed by whitespace or end-of-text to count as a sentence break. Each
+// returned sentence keeps its trailing whitespace, so joining the results
+// back with strings.Join(sentences, "") reconstructs text exactly.
+func splitSentences(text string) []string {
+	runes := []rune(text)
+	n := len(runes)
+	var sentences []string
+	start := 0
+	i := 0
+	for i < n {
+		if !isSentenceTerminator(runes[i]) {
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j < n && (isSentenceTerminator(runes[j]) || runes[j] == '"' || runes[j] == '\'' || runes[j] == ')') {
+			j++
+		}
+
+		if isAbbreviation(string(runes[start:j])) {
+			i = j
+			continue
+		}
+		if !isCJKTerminator(runes[i]) && j < n && !unicode.IsSpace(runes[j]) {
+			i = j
+			continue
+		}
+
+		for j < n && (runes[j] == ' ' || runes[j] == '\t') {
+			j++
+		}
+		sentences = append(sentences, string(runes[start:j]))
+		start = j
+		i = j
+	}
+	if start < n {
+		sentences = append(sentences, string(runes[start:]))
+	}
+	return sentences
+}
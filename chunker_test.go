@@ -0,0 +1,105 @@
+package summarizer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/powerivq/summarizer"
+)
+
+// Use a Gemini model name so these tests hit the character-based fallback
+// estimator and stay offline/deterministic instead of needing tiktoken-go's
+// encoding download.
+
+func TestChunkerRespectsBudgetAndSentenceBoundaries(t *testing.T) {
+	text := "Dr. Smith filed the motion. The court granted it in part. " +
+		strings.Repeat("Additional background material follows here. ", 40) +
+		"The case is now closed."
+
+	chunker := summarizer.NewChunker("gemini-1.5-pro", 60, 0)
+	chunks := chunker.Chunk(text, 0)
+
+	if len(chunks) < 2 {
+		t.Fatalf("expected input to split into multiple chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if chunk.Tokens > 60 {
+			t.Errorf("chunk %d has %d tokens, want <= 60", i, chunk.Tokens)
+		}
+		if strings.Contains(chunk.Text, "Dr. Smith") && !strings.Contains(chunk.Text, "Dr. Smith filed the motion.") {
+			t.Errorf("chunk %d split inside the \"Dr.\" abbreviation: %q", i, chunk.Text)
+		}
+	}
+	if strings.Join(func() []string {
+		texts := make([]string, len(chunks))
+		for i, c := range chunks {
+			texts[i] = c.Text
+		}
+		return texts
+	}(), "") != text {
+		t.Errorf("chunks do not reconstruct the original text")
+	}
+}
+
+func TestChunkerOverlap(t *testing.T) {
+	text := strings.Repeat("one two three four five. ", 30)
+
+	budget := 40
+	chunker := summarizer.NewChunker("gemini-1.5-pro", budget, 10)
+	chunks := chunker.Chunk(text, 0)
+	if len(chunks) < 2 {
+		t.Fatalf("expected input to split into multiple chunks, got %d", len(chunks))
+	}
+
+	for i := 1; i < len(chunks); i++ {
+		tail := strings.TrimSpace(chunks[i-1].Text)
+		words := strings.Fields(tail)
+		if len(words) == 0 {
+			continue
+		}
+		last := words[len(words)-1]
+		if !strings.Contains(chunks[i].Text, last) {
+			t.Errorf("chunk %d does not carry over overlap from chunk %d (expected to contain %q)", i, i-1, last)
+		}
+	}
+
+	for i, chunk := range chunks {
+		if chunk.Tokens > budget {
+			t.Errorf("chunk %d has %d tokens, exceeds budget %d", i, chunk.Tokens, budget)
+		}
+	}
+}
+
+// TestChunkerOverlapNeverExceedsBudget guards against an overlap tail that
+// leaves no room for the unit that follows it: a large overlap carried over
+// from the previous window, immediately followed by a unit nearly as large
+// as the budget on its own, must not be packed into the same window.
+func TestChunkerOverlapNeverExceedsBudget(t *testing.T) {
+	short := "one two three four five six seven eight. " // 11 tokens
+	long := strings.Repeat("word ", 23) + "end. "        // 30 tokens
+	text := strings.Repeat(short, 4) + long + strings.Repeat(short, 4)
+
+	budget := 40
+	chunker := summarizer.NewChunker("gemini-1.5-pro", budget, 30)
+	chunks := chunker.Chunk(text, 0)
+	if len(chunks) < 2 {
+		t.Fatalf("expected input to split into multiple chunks, got %d", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		if chunk.Tokens > budget {
+			t.Errorf("chunk %d has %d tokens, exceeds budget %d", i, chunk.Tokens, budget)
+		}
+	}
+}
+
+func TestChunkerNoSplitWhenUnderBudget(t *testing.T) {
+	chunker := summarizer.NewChunker("gemini-1.5-pro", 1000, 0)
+	chunks := chunker.Chunk("short text", 0)
+	if len(chunks) != 1 {
+		t.Fatalf("expected short text to fit in a single chunk, got %d", len(chunks))
+	}
+	if chunks[0].Text != "short text" {
+		t.Errorf("Chunk() = %q, want %q", chunks[0].Text, "short text")
+	}
+}
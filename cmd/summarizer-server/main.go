@@ -0,0 +1,54 @@
+// Command summarizer-server runs the OpenAI-compatible HTTP front end for
+// the summarizer, load-balancing across whichever Azure/OpenAI/Gemini
+// backends are configured via environment variables.
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/powerivq/summarizer"
+	"github.com/powerivq/summarizer/server"
+)
+
+func main() {
+	var accessConfigs []summarizer.AccessConfig
+	if token := os.Getenv("OPENAI_API_KEY"); token != "" {
+		accessConfigs = append(accessConfigs, summarizer.AccessConfig{
+			AuthToken: token,
+			APIType:   summarizer.APITypeOpenAI,
+		})
+	}
+	if token := os.Getenv("AZURE_OPENAI_API_KEY"); token != "" {
+		accessConfigs = append(accessConfigs, summarizer.AccessConfig{
+			AuthToken: token,
+			BaseURL:   os.Getenv("AZURE_OPENAI_BASE_URL"),
+			APIType:   summarizer.APITypeAzure,
+		})
+	}
+	for _, token := range strings.Split(os.Getenv("GCP_GEMINI_API_KEYS"), ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		accessConfigs = append(accessConfigs, summarizer.AccessConfig{
+			AuthToken: token,
+			APIType:   summarizer.APITypeGCPGemini,
+		})
+	}
+	if len(accessConfigs) == 0 {
+		log.Fatal("no backend credentials configured: set OPENAI_API_KEY, AZURE_OPENAI_API_KEY, and/or GCP_GEMINI_API_KEYS")
+	}
+
+	client := summarizer.NewClientNoCache(summarizer.Config{AccessConfigs: accessConfigs})
+	srv := server.New(client, os.Getenv("SUMMARIZER_AUTH_TOKEN"))
+
+	addr := os.Getenv("SUMMARIZER_SERVER_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+	log.Printf("summarizer-server listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, srv.Handler()))
+}
@@ -3,8 +3,8 @@ package summarizer
 type APIType string
 
 const (
-	APITypeOpenAI  APIType = "OPEN_AI"
-	APITypeAzure   APIType = "AZURE"
+	APITypeOpenAI    APIType = "OPEN_AI"
+	APITypeAzure     APIType = "AZURE"
 	APITypeGCPGemini APIType = "GCP_GEMINI"
 )
 
@@ -12,6 +12,18 @@ type AccessConfig struct {
 	AuthToken string
 	BaseURL   string
 	APIType   APIType
+
+	// Weight controls how often this backend is picked relative to other
+	// backends at the same Priority tier (weighted random selection).
+	// Defaults to 1 if unset.
+	Weight int
+
+	// Priority controls which backends are tried first: backends with a
+	// higher Priority are tried before lower ones, with ties within a tier
+	// broken by Weight. Defaults to a fixed per-APIType priority (Azure,
+	// then OpenAI, then Gemini) if unset, matching the router's legacy
+	// ordering.
+	Priority int
 }
 
 type Config struct {
@@ -1,26 +1,42 @@
 package summarizer
 
+type GeminiInlineData struct {
+	MimeType string `json:"mime_type"`
+	Data     string `json:"data"`
+}
+
+type GeminiFileData struct {
+	MimeType string `json:"mime_type"`
+	FileURI  string `json:"file_uri"`
+}
+
+// GeminiRequestContentsMessagePart is a single part of a message: exactly
+// one of Text, InlineData, or FileData should be set. InlineData carries a
+// small attachment as base64; FileData references one already uploaded via
+// the Gemini Files API.
 type GeminiRequestContentsMessagePart struct {
-	Text string `json:"text"`
+	Text       string            `json:"text,omitempty"`
+	InlineData *GeminiInlineData `json:"inline_data,omitempty"`
+	FileData   *GeminiFileData   `json:"file_data,omitempty"`
 }
 
 type GeminiRequestContentsMessage struct {
-	Role string `json:"role"`
+	Role  string                             `json:"role"`
 	Parts []GeminiRequestContentsMessagePart `json:"parts"`
 }
 
 type GeminiRequestGenerationConfig struct {
-	Temperature     float32                  `json:"temperature"`
-	TopK            int                      `json:"top_k"`
-	TopP            float32                  `json:"top_p"`
-	MaxOutputTokens int                      `json:"max_output_tokens"`
-	StopSequences   []string                 `json:"stop_sequences"`
+	Temperature     float32  `json:"temperature"`
+	TopK            int      `json:"top_k"`
+	TopP            float32  `json:"top_p"`
+	MaxOutputTokens int      `json:"max_output_tokens"`
+	StopSequences   []string `json:"stop_sequences"`
 }
 
 type GeminiRequest struct {
-	Messages []GeminiRequestContentsMessage       `json:"contents"`
-	Config          GeminiRequestGenerationConfig `json:"generationConfig"`
-	SafetySettings  []map[string]interface{}      `json:"safetySettings"`
+	Messages       []GeminiRequestContentsMessage `json:"contents"`
+	Config         GeminiRequestGenerationConfig  `json:"generationConfig"`
+	SafetySettings []map[string]interface{}       `json:"safetySettings"`
 }
 
 type GeminiResponseCandidateContentPart struct {
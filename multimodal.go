@@ -0,0 +1,244 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"regexp"
+	"time"
+)
+
+// Attachment is a single non-text input to SummarizeMultimodal, such as an
+// image, audio clip, or PDF page. MIME must be a type Gemini accepts for
+// inline_data/file_data (e.g. "image/png", "application/pdf").
+type Attachment struct {
+	MIME string
+	Data []byte
+}
+
+// geminiInlineThreshold is the largest attachment SummarizeMultimodal will
+// send as base64 inline_data. Anything bigger is uploaded via the Files API
+// and referenced by file_data, matching Gemini's own guidance that inline
+// payloads should stay well under its 20MB request limit.
+const geminiInlineThreshold = 4 * 1024 * 1024
+
+// defaultGeminiFileCacheTTL is how long an uploaded file's cache entry is
+// trusted before SummarizeMultimodal re-uploads it, used when
+// SummarizeOptions.GeminiFileCacheTTL is unset. It matches Gemini's own
+// Files API retention (uploads are deleted after 48 hours), since trusting
+// the cache past that would resolve to a file_uri Gemini has already
+// deleted; resolveGeminiFilePart also re-uploads on demand if a cached
+// entry turns out stale regardless of its TTL.
+const defaultGeminiFileCacheTTL = 48 * time.Hour
+
+// geminiFileNotFoundMatcher matches the generateContent failure Gemini
+// returns when a referenced file_uri has expired or been deleted, so
+// SummarizeMultimodal knows to re-upload and retry once instead of
+// surfacing a stale-file error to the caller.
+var geminiFileNotFoundMatcher = regexp.MustCompile(`(?i)"code":\s*(403|404)|NOT_FOUND|PERMISSION_DENIED`)
+
+func isGeminiFileNotFoundError(err error) bool {
+	return err != nil && geminiFileNotFoundMatcher.MatchString(err.Error())
+}
+
+// geminiFileCacheEntry is the JSON value stored in Cache for a previously
+// uploaded attachment, keyed by content hash and account so two Gemini
+// accounts never share (or leak) each other's file_uri.
+type geminiFileCacheEntry struct {
+	FileURI   string    `json:"file_uri"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// geminiFileCacheKey identifies an uploaded attachment by content and
+// account. The token is hashed, rather than embedded raw, so a Cache backed
+// by an external store (Redis, etc.) never persists a credential.
+func geminiFileCacheKey(token string, data []byte) string {
+	return "gemini-file:" + GetSHA1Hash(data) + ":" + GetMD5Hash(token)
+}
+
+// SummarizeMultimodal summarizes text alongside one or more attachments
+// (images, audio, PDFs, ...) using Gemini, the only backend this module
+// talks to that accepts non-text input. It does not window long text the
+// way SummarizeWith does: attachments are meant to accompany a single
+// prompt-sized pass, not a recursive chain.
+//
+// opts.GeminiFileCacheTTL overrides how long an uploaded attachment's
+// file_uri is trusted before it's re-uploaded, defaulting to
+// defaultGeminiFileCacheTTL when unset. Independent of that TTL, if Gemini
+// reports a cached file_uri as gone (it deletes uploads after 48 hours on
+// its own schedule), SummarizeMultimodal re-uploads the attachment and
+// retries the call once.
+func (c *Client) SummarizeMultimodal(ctx context.Context, text string, attachments []Attachment, opts SummarizeOptions) (*string, error) {
+	if len(c.gcpTokens) == 0 {
+		return nil, errors.New("SummarizeMultimodal requires a configured Gemini backend")
+	}
+	if opts.Strategy == nil {
+		opts.Strategy = CaseBriefStrategy{}
+	}
+	token := c.gcpTokens[rand.Intn(len(c.gcpTokens))]
+	ttl := opts.GeminiFileCacheTTL
+	if ttl <= 0 {
+		ttl = defaultGeminiFileCacheTTL
+	}
+
+	prompt := opts.Strategy.Build(text, opts.Language) + text
+	parts, err := c.resolveGeminiParts(ctx, token, prompt, attachments, ttl, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := doRequestGeminiPartsWithContext(ctx, token, parts, opts)
+	if err != nil && isGeminiFileNotFoundError(err) {
+		log.Printf("Gemini file_uri expired or missing, re-uploading attachments: %s", err)
+		parts, err = c.resolveGeminiParts(ctx, token, prompt, attachments, ttl, true)
+		if err != nil {
+			return nil, err
+		}
+		result, err = doRequestGeminiPartsWithContext(ctx, token, parts, opts)
+	}
+	return result, err
+}
+
+// resolveGeminiParts turns prompt and attachments into the message parts for
+// a single generateContent call, uploading/caching each attachment via
+// resolveGeminiFilePart.
+func (c *Client) resolveGeminiParts(ctx context.Context, token string, prompt string, attachments []Attachment, ttl time.Duration, forceReupload bool) ([]GeminiRequestContentsMessagePart, error) {
+	parts := []GeminiRequestContentsMessagePart{{Text: prompt}}
+	for _, attachment := range attachments {
+		part, err := c.resolveGeminiFilePart(ctx, token, attachment, ttl, forceReupload)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// resolveGeminiFilePart turns attachment into a message part: small
+// attachments are inlined as base64, larger ones are uploaded via the Files
+// API (with the result cached and deduped by content hash) and referenced
+// by file_uri. forceReupload skips the cache lookup, for the retry after a
+// stale file_uri was rejected.
+func (c *Client) resolveGeminiFilePart(ctx context.Context, token string, attachment Attachment, ttl time.Duration, forceReupload bool) (GeminiRequestContentsMessagePart, error) {
+	if len(attachment.Data) <= geminiInlineThreshold {
+		return GeminiRequestContentsMessagePart{
+			InlineData: &GeminiInlineData{
+				MimeType: attachment.MIME,
+				Data:     base64.StdEncoding.EncodeToString(attachment.Data),
+			},
+		}, nil
+	}
+
+	cacheKey := geminiFileCacheKey(token, attachment.Data)
+	if !forceReupload {
+		if cached := c.cache.Get(cacheKey); cached != nil {
+			var entry geminiFileCacheEntry
+			if err := json.Unmarshal([]byte(*cached), &entry); err == nil && time.Now().Before(entry.ExpiresAt) {
+				return GeminiRequestContentsMessagePart{
+					FileData: &GeminiFileData{MimeType: attachment.MIME, FileURI: entry.FileURI},
+				}, nil
+			}
+		}
+	}
+
+	fileURI, err := uploadGeminiFile(ctx, token, attachment)
+	if err != nil {
+		return GeminiRequestContentsMessagePart{}, err
+	}
+
+	entry := geminiFileCacheEntry{FileURI: fileURI, ExpiresAt: time.Now().Add(ttl)}
+	if encoded, err := json.Marshal(entry); err == nil {
+		c.cache.Set(cacheKey, string(encoded))
+	}
+	return GeminiRequestContentsMessagePart{
+		FileData: &GeminiFileData{MimeType: attachment.MIME, FileURI: fileURI},
+	}, nil
+}
+
+type geminiFileUploadResponse struct {
+	File struct {
+		URI string `json:"uri"`
+	} `json:"file"`
+}
+
+// uploadGeminiFile uploads attachment to the Gemini Files API using a
+// multipart/related (RFC 2387) body: a JSON metadata part followed by the
+// raw file bytes, matching the "simple multipart upload" flow Gemini's
+// Files API documents.
+func uploadGeminiFile(ctx context.Context, token string, attachment Attachment) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	boundary := writer.Boundary()
+
+	metadataHeader := make(textproto.MIMEHeader)
+	metadataHeader.Set("Content-Type", "application/json")
+	metadataPart, err := writer.CreatePart(metadataHeader)
+	if err != nil {
+		return "", fmt.Errorf("Gemini file upload metadata part: %s", err)
+	}
+	metadata, err := json.Marshal(map[string]interface{}{
+		"file": map[string]string{"mime_type": attachment.MIME},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Gemini file upload metadata encode: %s", err)
+	}
+	if _, err := metadataPart.Write(metadata); err != nil {
+		return "", fmt.Errorf("Gemini file upload metadata write: %s", err)
+	}
+
+	fileHeader := make(textproto.MIMEHeader)
+	fileHeader.Set("Content-Type", attachment.MIME)
+	filePart, err := writer.CreatePart(fileHeader)
+	if err != nil {
+		return "", fmt.Errorf("Gemini file upload data part: %s", err)
+	}
+	if _, err := filePart.Write(attachment.Data); err != nil {
+		return "", fmt.Errorf("Gemini file upload data write: %s", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("Gemini file upload close: %s", err)
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		"https://generativelanguage.googleapis.com/upload/v1beta/files?key="+token,
+		&body)
+	if err != nil {
+		return "", fmt.Errorf("Gemini file upload request: %s", err)
+	}
+	request.Header.Set("Content-Type", "multipart/related; boundary="+boundary)
+
+	client := &http.Client{Timeout: 120 * time.Second}
+	response, err := client.Do(request)
+	if err != nil || response == nil {
+		return "", fmt.Errorf("Gemini file upload failure: %s", err)
+	}
+	defer response.Body.Close()
+
+	respBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("Gemini file upload read response: %s", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Gemini file upload status: %d\nresponse: %s", response.StatusCode, string(respBody))
+	}
+
+	var result geminiFileUploadResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("Gemini file upload parse response: %s", err)
+	}
+	if result.File.URI == "" {
+		return "", errors.New("Gemini file upload: no file URI in response")
+	}
+	return result.File.URI, nil
+}
@@ -0,0 +1,47 @@
+package summarizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGeminiFileCacheKeyStableAndAccountScoped(t *testing.T) {
+	data := []byte("pdf bytes")
+
+	if got, want := geminiFileCacheKey("token-a", data), geminiFileCacheKey("token-a", data); got != want {
+		t.Errorf("geminiFileCacheKey() not stable across calls: %q != %q", got, want)
+	}
+	if geminiFileCacheKey("token-a", data) == geminiFileCacheKey("token-b", data) {
+		t.Error("geminiFileCacheKey() collided across different accounts for the same content")
+	}
+	if geminiFileCacheKey("token-a", data) == geminiFileCacheKey("token-a", []byte("other bytes")) {
+		t.Error("geminiFileCacheKey() collided across different content for the same account")
+	}
+}
+
+func TestIsGeminiFileNotFoundError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"unrelated error", errors.New("connection reset"), false},
+		{"404 status body", errors.New(`Gemini status: 404` + "\n" + `response: {"error":{"code":404,"message":"File not found","status":"NOT_FOUND"}}`), true},
+		{"403 permission denied", errors.New(`Gemini status: 403` + "\n" + `response: {"error":{"code":403,"status":"PERMISSION_DENIED"}}`), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isGeminiFileNotFoundError(tc.err); got != tc.want {
+				t.Errorf("isGeminiFileNotFoundError(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeMultimodalDefaultFileCacheTTLMatchesGeminiRetention(t *testing.T) {
+	if defaultGeminiFileCacheTTL != 48*time.Hour {
+		t.Errorf("defaultGeminiFileCacheTTL = %v, want 48h to match Gemini's own Files API retention", defaultGeminiFileCacheTTL)
+	}
+}
@@ -0,0 +1,165 @@
+package summarizer
+
+import (
+	"bytes"
+	"log"
+	"text/template"
+	"time"
+)
+
+// Language is the natural language a PromptStrategy should write its
+// summary in. The zero value, LanguageAuto, asks the strategy to detect the
+// language from the input text the way Summarize always has.
+type Language string
+
+const (
+	LanguageAuto    Language = ""
+	LanguageEnglish Language = "en"
+	LanguageChinese Language = "zh"
+)
+
+// resolveLanguage returns lang if the caller pinned one, otherwise detects it
+// from text using the same character-count heuristic Summarize has always
+// used.
+func resolveLanguage(text string, lang Language) Language {
+	if lang != LanguageAuto {
+		return lang
+	}
+	chineseChars := len(chineseMatcher.FindAllString(text, -1))
+	englishChars := len(englishMatcher.FindAllString(text, -1))
+	if chineseChars >= englishChars {
+		return LanguageChinese
+	}
+	return LanguageEnglish
+}
+
+// PromptStrategy builds the instruction prompt prepended to the source text
+// (or, for the windowed path, to each window of it). text is only used to
+// auto-detect the language when lang is LanguageAuto; the prompt must not
+// embed it, since the same prompt is reused as a prefix for every window.
+type PromptStrategy interface {
+	Build(text string, lang Language) string
+}
+
+// CaseBriefStrategy asks for a legal case brief: facts, procedural history,
+// holdings, rationales, and final disposition. This is the prompt Summarize
+// has always hardcoded.
+type CaseBriefStrategy struct{}
+
+func (s CaseBriefStrategy) Build(text string, lang Language) string {
+	if resolveLanguage(text, lang) == LanguageChinese {
+		return "In Chinese, write a case brief for the following judgment, includes the facts, procedural history, holdings, rationales for each holding, and final disposition: \n\n"
+	}
+	return "Write a case brief for the following judgment, includes the facts, procedural history, holdings, rationales for each holding, and final disposition: \n\n"
+}
+
+// TLDRStrategy asks for a short, plain-language summary.
+type TLDRStrategy struct{}
+
+func (s TLDRStrategy) Build(text string, lang Language) string {
+	if resolveLanguage(text, lang) == LanguageChinese {
+		return "用中文为以下内容写一个简短的摘要：\n\n"
+	}
+	return "Write a short TL;DR summary of the following: \n\n"
+}
+
+// BulletPointsStrategy asks for the key points as a bullet list.
+type BulletPointsStrategy struct{}
+
+func (s BulletPointsStrategy) Build(text string, lang Language) string {
+	if resolveLanguage(text, lang) == LanguageChinese {
+		return "用中文以要点列表的形式列出以下内容的要点：\n\n"
+	}
+	return "Summarize the key points of the following as a bullet point list: \n\n"
+}
+
+// CustomTemplateStrategy renders a caller-supplied text/template to produce
+// the instruction prompt, so callers outside the legal-judgment use case can
+// drive the module without forking it. The template is executed with a
+// struct exposing Language (the resolved Language for this call); it does
+// not receive the source text, since the same rendered prompt is reused as
+// a prefix for every window.
+type CustomTemplateStrategy struct {
+	Template *template.Template
+}
+
+// NewCustomTemplateStrategy parses tmpl as a text/template and returns a
+// strategy that renders it per call.
+func NewCustomTemplateStrategy(name string, tmpl string) (*CustomTemplateStrategy, error) {
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomTemplateStrategy{Template: t}, nil
+}
+
+type customTemplateData struct {
+	Language Language
+}
+
+func (s *CustomTemplateStrategy) Build(text string, lang Language) string {
+	var buf bytes.Buffer
+	data := customTemplateData{Language: resolveLanguage(text, lang)}
+	if err := s.Template.Execute(&buf, data); err != nil {
+		log.Printf("CustomTemplateStrategy execute error: %s", err)
+		return ""
+	}
+	return buf.String()
+}
+
+// Chain selects how SummarizeWith handles input too long for a single
+// completion call.
+type Chain string
+
+const (
+	// ChainRecursive summarizes each window in turn, then re-summarizes the
+	// concatenated partials, recursing until they fit in one pass. This is
+	// the original, default behavior.
+	ChainRecursive Chain = ""
+	// ChainMapReduce summarizes every window in parallel (bounded by
+	// SummarizeOptions.Concurrency), then reduces the concatenated partials
+	// via ChainRecursive: a single pass if they fit in one window, otherwise
+	// recursing as ChainRecursive does.
+	ChainMapReduce Chain = "MAP_REDUCE"
+	// ChainRefine walks windows sequentially, feeding (runningSummary,
+	// nextWindow) into a refine prompt so context accumulates without a
+	// final reduce pass.
+	ChainRefine Chain = "REFINE"
+)
+
+// SummarizeOptions configures a SummarizeWith call: which PromptStrategy
+// builds the instruction, which model/sampling parameters drive the
+// backend, which Chain windows long input, and which language to write in.
+// The zero value is valid and reproduces Summarize's defaults
+// (CaseBriefStrategy, ChainRecursive, auto-detected language, backend-
+// default model and sampling).
+type SummarizeOptions struct {
+	Strategy PromptStrategy
+	Model    string
+	// Temperature is a pointer so an explicit 0 (common for deterministic
+	// summarization) is distinguishable from "unset"; nil leaves the
+	// backend's own default in place. Use a literal address, e.g.
+	// summarizer.Float32Ptr(0).
+	Temperature     *float32
+	MaxOutputTokens int
+	Language        Language
+	Chain           Chain
+	// Concurrency bounds how many windows ChainMapReduce summarizes at
+	// once. Defaults to 4 if unset.
+	Concurrency int
+	// GeminiFileCacheTTL overrides how long SummarizeMultimodal trusts a
+	// cached attachment upload before re-uploading it. Defaults to
+	// defaultGeminiFileCacheTTL if unset. Only consulted by
+	// SummarizeMultimodal.
+	GeminiFileCacheTTL time.Duration
+}
+
+// Float32Ptr returns a pointer to f, for populating SummarizeOptions.Temperature
+// inline.
+func Float32Ptr(f float32) *float32 {
+	return &f
+}
+
+func defaultSummarizeOptions() SummarizeOptions {
+	return SummarizeOptions{Strategy: CaseBriefStrategy{}}
+}
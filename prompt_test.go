@@ -0,0 +1,54 @@
+package summarizer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/powerivq/summarizer"
+)
+
+func TestCustomTemplateStrategyBuild(t *testing.T) {
+	strategy, err := summarizer.NewCustomTemplateStrategy("test", "Summarize in {{.Language}}:\n\n")
+	if err != nil {
+		t.Fatalf("NewCustomTemplateStrategy() error = %v", err)
+	}
+
+	got := strategy.Build("ignored", summarizer.LanguageEnglish)
+	want := "Summarize in en:\n\n"
+	if got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+}
+
+func TestCustomTemplateStrategyBuildInvalidTemplateReturnsEmpty(t *testing.T) {
+	strategy, err := summarizer.NewCustomTemplateStrategy("bad", "{{.NoSuchField}}")
+	if err != nil {
+		t.Fatalf("NewCustomTemplateStrategy() error = %v", err)
+	}
+
+	if got := strategy.Build("text", summarizer.LanguageAuto); got != "" {
+		t.Errorf("Build() = %q, want empty string on template execution error", got)
+	}
+}
+
+func TestTLDRStrategyLanguageSelection(t *testing.T) {
+	strategy := summarizer.TLDRStrategy{}
+
+	if got := strategy.Build("anything", summarizer.LanguageChinese); !strings.Contains(got, "中") {
+		t.Errorf("Build(LanguageChinese) = %q, want Chinese prompt", got)
+	}
+	if got := strategy.Build("anything", summarizer.LanguageEnglish); strings.Contains(got, "中") {
+		t.Errorf("Build(LanguageEnglish) = %q, want English prompt", got)
+	}
+}
+
+func TestBulletPointsStrategyAutoDetectsLanguage(t *testing.T) {
+	strategy := summarizer.BulletPointsStrategy{}
+
+	if got := strategy.Build("这是一段中文文本", summarizer.LanguageAuto); !strings.Contains(got, "中") {
+		t.Errorf("Build() with Chinese text = %q, want Chinese prompt", got)
+	}
+	if got := strategy.Build("this is an english text", summarizer.LanguageAuto); strings.Contains(got, "中") {
+		t.Errorf("Build() with English text = %q, want English prompt", got)
+	}
+}
@@ -0,0 +1,287 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// defaultPriorityByAPIType preserves the router's legacy Azure-then-OpenAI-
+// then-Gemini ordering for AccessConfigs that don't set Priority.
+var defaultPriorityByAPIType = map[APIType]int{
+	APITypeAzure:     30,
+	APITypeOpenAI:    20,
+	APITypeGCPGemini: 10,
+}
+
+const (
+	defaultCircuitBreakerThreshold = 3
+	defaultCircuitBreakerCooldown  = 30 * time.Second
+	maxAttemptsPerTier             = 3
+	latencyEMAAlpha                = 0.2
+)
+
+// RouteEvent describes one attempt the Router made to serve a request, for
+// callers that want to observe routing decisions (e.g. for metrics/logging).
+type RouteEvent struct {
+	Backend  string
+	Priority int
+	Attempt  int
+	Latency  time.Duration
+	Err      error
+}
+
+// routerEntry tracks a single Backend's routing weight/priority plus the
+// health bookkeeping (success rate, latency EMA, consecutive failures) the
+// Router uses to open and close its circuit breaker.
+type routerEntry struct {
+	backend  Backend
+	apiType  APIType
+	weight   int
+	priority int
+
+	mu                  sync.Mutex
+	successes           int
+	failures            int
+	consecutiveFailures int
+	latencyEMA          time.Duration
+	circuitOpenUntil    time.Time
+}
+
+func newRouterEntry(backend Backend, cfg AccessConfig) *routerEntry {
+	weight := cfg.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+	priority := cfg.Priority
+	if priority == 0 {
+		priority = defaultPriorityByAPIType[cfg.APIType]
+	}
+	return &routerEntry{backend: backend, apiType: cfg.APIType, weight: weight, priority: priority}
+}
+
+// available reports whether the entry's circuit is closed and its backend
+// self-reports healthy.
+func (e *routerEntry) available(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return now.After(e.circuitOpenUntil) && e.backend.Healthy()
+}
+
+func (e *routerEntry) record(err error, latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.latencyEMA == 0 {
+		e.latencyEMA = latency
+	} else {
+		e.latencyEMA = time.Duration((1-latencyEMAAlpha)*float64(e.latencyEMA) + latencyEMAAlpha*float64(latency))
+	}
+
+	if err == nil {
+		e.successes++
+		e.consecutiveFailures = 0
+		return
+	}
+	e.failures++
+	e.consecutiveFailures++
+	if e.consecutiveFailures >= defaultCircuitBreakerThreshold {
+		e.circuitOpenUntil = time.Now().Add(defaultCircuitBreakerCooldown)
+	}
+}
+
+// Router picks a Backend to serve each completion request, tracking per-
+// backend health so it can open a circuit breaker for a cool-down window
+// after repeated failures instead of hammering a backend that is down.
+// Backends are tried in descending Priority tiers; within a tier, a healthy
+// backend is picked by weighted random selection.
+type Router struct {
+	entries []*routerEntry
+	logger  LLMLogger
+
+	// OnRoute, if set, is called after every attempt (success or failure)
+	// so callers can observe routing decisions.
+	OnRoute func(RouteEvent)
+}
+
+func NewRouter(entries []*routerEntry, logger LLMLogger) *Router {
+	return &Router{entries: entries, logger: logger}
+}
+
+// AddBackend registers backend with the Router under cfg's Weight/Priority
+// (or their defaults), so callers can route to a custom Backend implementation
+// without needing to construct the unexported routerEntry type themselves.
+// Intended for setup time, before Complete is driven concurrently; it does
+// not itself synchronize with in-flight Complete calls.
+func (r *Router) AddBackend(backend Backend, cfg AccessConfig) {
+	r.entries = append(r.entries, newRouterEntry(backend, cfg))
+}
+
+// tiersDescending groups entries by Priority, highest first.
+func (r *Router) tiersDescending() [][]*routerEntry {
+	byPriority := map[int][]*routerEntry{}
+	var priorities []int
+	for _, e := range r.entries {
+		if _, ok := byPriority[e.priority]; !ok {
+			priorities = append(priorities, e.priority)
+		}
+		byPriority[e.priority] = append(byPriority[e.priority], e)
+	}
+	for i := 0; i < len(priorities); i++ {
+		for j := i + 1; j < len(priorities); j++ {
+			if priorities[j] > priorities[i] {
+				priorities[i], priorities[j] = priorities[j], priorities[i]
+			}
+		}
+	}
+	var tiers [][]*routerEntry
+	for _, p := range priorities {
+		tiers = append(tiers, byPriority[p])
+	}
+	return tiers
+}
+
+// pickWeighted returns a weighted-random available entry from tier, or nil
+// if none are available.
+func pickWeighted(tier []*routerEntry, now time.Time) *routerEntry {
+	var available []*routerEntry
+	totalWeight := 0
+	for _, e := range tier {
+		if e.available(now) {
+			available = append(available, e)
+			totalWeight += e.weight
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+	target := rand.Intn(totalWeight)
+	for _, e := range available {
+		target -= e.weight
+		if target < 0 {
+			return e
+		}
+	}
+	return available[len(available)-1]
+}
+
+// Complete routes req to a backend, retrying within a priority tier up to
+// maxAttemptsPerTier times before falling through to the next tier, the
+// same retry budget requestGpt used to give each fixed pool.
+func (r *Router) Complete(ctx context.Context, req Request) (Response, error) {
+	var lastErr error
+	for _, tier := range r.tiersDescending() {
+		for attempt := 0; attempt < maxAttemptsPerTier; attempt++ {
+			entry := pickWeighted(tier, time.Now())
+			if entry == nil {
+				break
+			}
+
+			start := time.Now()
+			resp, err := entry.backend.Complete(ctx, req)
+			latency := time.Since(start)
+			entry.record(err, latency)
+
+			if r.OnRoute != nil {
+				r.OnRoute(RouteEvent{Backend: entry.backend.Name(), Priority: entry.priority, Attempt: attempt, Latency: latency, Err: err})
+			}
+
+			if err == nil {
+				r.logger.Log(req.Prompt, resp.Content, entry.apiType)
+				return resp, nil
+			}
+
+			lastErr = err
+			log.Printf("%s error: %s", entry.backend.Name(), err)
+
+			apiError := &openai.APIError{}
+			if errors.As(err, &apiError) && apiError.HTTPStatusCode == 400 {
+				break
+			}
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no healthy backend available")
+	}
+	return Response{}, fmt.Errorf("all retries have failed: %w", lastErr)
+}
+
+// streamableEntries returns the entries in tier whose Backend implements
+// StreamingBackend, preserving order.
+func streamableEntries(tier []*routerEntry) []*routerEntry {
+	var out []*routerEntry
+	for _, e := range tier {
+		if _, ok := e.backend.(StreamingBackend); ok {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// CompleteStream routes req the same way Complete does - descending priority
+// tiers, weighted selection, circuit breaker bookkeeping, and the same
+// maxAttemptsPerTier retry budget - but only considers entries whose Backend
+// implements StreamingBackend, and delivers the completion incrementally via
+// onDelta instead of returning it all at once. onDelta is called with the
+// APIType of the backend that produced the delta, so callers streaming to
+// multiple consumers can attribute each one.
+func (r *Router) CompleteStream(ctx context.Context, req Request, onDelta func(provider APIType, delta string)) error {
+	var lastErr error
+	for _, tier := range r.tiersDescending() {
+		streamable := streamableEntries(tier)
+		for attempt := 0; attempt < maxAttemptsPerTier; attempt++ {
+			entry := pickWeighted(streamable, time.Now())
+			if entry == nil {
+				break
+			}
+			sb := entry.backend.(StreamingBackend)
+
+			var full strings.Builder
+			var emitted bool
+			start := time.Now()
+			err := sb.CompleteStream(ctx, req, func(delta string) {
+				emitted = true
+				full.WriteString(delta)
+				onDelta(entry.apiType, delta)
+			})
+			latency := time.Since(start)
+			entry.record(err, latency)
+
+			if r.OnRoute != nil {
+				r.OnRoute(RouteEvent{Backend: entry.backend.Name(), Priority: entry.priority, Attempt: attempt, Latency: latency, Err: err})
+			}
+
+			if err == nil {
+				r.logger.Log(req.Prompt, full.String(), entry.apiType)
+				return nil
+			}
+
+			lastErr = err
+			log.Printf("%s stream error: %s", entry.backend.Name(), err)
+
+			if emitted {
+				// The caller has already received part of a completion from
+				// this backend; falling through to another backend would
+				// make onDelta replay a second completion on top of it, so
+				// surface the error instead of retrying.
+				return fmt.Errorf("stream failed after partial output: %w", err)
+			}
+
+			apiError := &openai.APIError{}
+			if errors.As(err, &apiError) && apiError.HTTPStatusCode == 400 {
+				break
+			}
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no healthy streaming backend available")
+	}
+	return fmt.Errorf("all retries have failed: %w", lastErr)
+}
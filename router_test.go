@@ -0,0 +1,193 @@
+package summarizer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/powerivq/summarizer"
+)
+
+// countingBackend is a fake Backend whose Complete behavior and call count
+// are fully controlled by the test, so Router's weighted routing and
+// circuit-breaker behavior can be exercised without a real provider.
+type countingBackend struct {
+	name  string
+	fail  bool
+	calls int
+}
+
+func (b *countingBackend) Name() string  { return b.name }
+func (b *countingBackend) Healthy() bool { return true }
+func (b *countingBackend) Complete(ctx context.Context, req summarizer.Request) (summarizer.Response, error) {
+	b.calls++
+	if b.fail {
+		return summarizer.Response{}, errors.New("backend failure")
+	}
+	return summarizer.Response{Content: "ok from " + b.name}, nil
+}
+
+func TestRouterAddBackendWeightedRouting(t *testing.T) {
+	router := summarizer.NewRouter(nil, summarizer.NoOpLogger{})
+
+	heavy := &countingBackend{name: "heavy"}
+	light := &countingBackend{name: "light"}
+	router.AddBackend(heavy, summarizer.AccessConfig{Weight: 9})
+	router.AddBackend(light, summarizer.AccessConfig{Weight: 1})
+
+	for i := 0; i < 200; i++ {
+		if _, err := router.Complete(context.Background(), summarizer.Request{Prompt: "hi"}); err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+	}
+
+	if heavy.calls <= light.calls {
+		t.Errorf("heavy (weight 9) got %d calls, light (weight 1) got %d; want heavy to dominate", heavy.calls, light.calls)
+	}
+}
+
+func TestRouterPriorityTiersFallThrough(t *testing.T) {
+	router := summarizer.NewRouter(nil, summarizer.NoOpLogger{})
+
+	primary := &countingBackend{name: "primary", fail: true}
+	fallback := &countingBackend{name: "fallback"}
+	router.AddBackend(primary, summarizer.AccessConfig{Priority: 100})
+	router.AddBackend(fallback, summarizer.AccessConfig{Priority: 1})
+
+	resp, err := router.Complete(context.Background(), summarizer.Request{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Content != "ok from fallback" {
+		t.Errorf("Complete() content = %q, want the lower-priority backend's response once the higher-priority one exhausts its retries", resp.Content)
+	}
+}
+
+// countingStreamingBackend is a fake StreamingBackend whose CompleteStream
+// behavior and call count are fully controlled by the test.
+type countingStreamingBackend struct {
+	name   string
+	fail   bool
+	deltas []string
+	calls  int
+
+	// failAfterDeltas, if set, emits these deltas and then fails mid-stream,
+	// instead of failing before emitting anything.
+	failAfterDeltas []string
+}
+
+func (b *countingStreamingBackend) Name() string  { return b.name }
+func (b *countingStreamingBackend) Healthy() bool { return true }
+func (b *countingStreamingBackend) Complete(ctx context.Context, req summarizer.Request) (summarizer.Response, error) {
+	b.calls++
+	return summarizer.Response{Content: "ok from " + b.name}, nil
+}
+func (b *countingStreamingBackend) CompleteStream(ctx context.Context, req summarizer.Request, onDelta func(string)) error {
+	b.calls++
+	if len(b.failAfterDeltas) > 0 {
+		for _, delta := range b.failAfterDeltas {
+			onDelta(delta)
+		}
+		return errors.New("stream failure")
+	}
+	if b.fail {
+		return errors.New("stream failure")
+	}
+	for _, delta := range b.deltas {
+		onDelta(delta)
+	}
+	return nil
+}
+
+func TestRouterCompleteStreamDeliversDeltasWithProvider(t *testing.T) {
+	router := summarizer.NewRouter(nil, summarizer.NoOpLogger{})
+	backend := &countingStreamingBackend{name: "streamer", deltas: []string{"hello ", "world"}}
+	router.AddBackend(backend, summarizer.AccessConfig{APIType: summarizer.APITypeOpenAI})
+
+	var got []string
+	var provider summarizer.APIType
+	err := router.CompleteStream(context.Background(), summarizer.Request{Prompt: "hi"}, func(p summarizer.APIType, delta string) {
+		provider = p
+		got = append(got, delta)
+	})
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "hello " || got[1] != "world" {
+		t.Errorf("CompleteStream() deltas = %v, want [\"hello \", \"world\"]", got)
+	}
+	if provider != summarizer.APITypeOpenAI {
+		t.Errorf("CompleteStream() provider = %q, want %q", provider, summarizer.APITypeOpenAI)
+	}
+}
+
+func TestRouterCompleteStreamSkipsNonStreamingBackends(t *testing.T) {
+	router := summarizer.NewRouter(nil, summarizer.NoOpLogger{})
+	router.AddBackend(&countingBackend{name: "non-streaming"}, summarizer.AccessConfig{})
+
+	err := router.CompleteStream(context.Background(), summarizer.Request{Prompt: "hi"}, func(summarizer.APIType, string) {})
+	if err == nil {
+		t.Fatal("CompleteStream() error = nil, want an error since no registered backend can stream")
+	}
+}
+
+func TestRouterCompleteStreamFallsThroughOnFailure(t *testing.T) {
+	router := summarizer.NewRouter(nil, summarizer.NoOpLogger{})
+	primary := &countingStreamingBackend{name: "primary", fail: true}
+	fallback := &countingStreamingBackend{name: "fallback", deltas: []string{"ok"}}
+	router.AddBackend(primary, summarizer.AccessConfig{Priority: 100})
+	router.AddBackend(fallback, summarizer.AccessConfig{Priority: 1})
+
+	var got []string
+	err := router.CompleteStream(context.Background(), summarizer.Request{Prompt: "hi"}, func(p summarizer.APIType, delta string) {
+		got = append(got, delta)
+	})
+	if err != nil {
+		t.Fatalf("CompleteStream() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "ok" {
+		t.Errorf("CompleteStream() deltas = %v, want the fallback's delta once the primary exhausts its retries", got)
+	}
+}
+
+func TestRouterCompleteStreamDoesNotRetryAfterPartialDelivery(t *testing.T) {
+	router := summarizer.NewRouter(nil, summarizer.NoOpLogger{})
+	primary := &countingStreamingBackend{name: "primary", failAfterDeltas: []string{"partial "}}
+	fallback := &countingStreamingBackend{name: "fallback", deltas: []string{"ok"}}
+	router.AddBackend(primary, summarizer.AccessConfig{Priority: 100})
+	router.AddBackend(fallback, summarizer.AccessConfig{Priority: 1})
+
+	var got []string
+	err := router.CompleteStream(context.Background(), summarizer.Request{Prompt: "hi"}, func(p summarizer.APIType, delta string) {
+		got = append(got, delta)
+	})
+	if err == nil {
+		t.Fatal("CompleteStream() error = nil, want an error once the primary fails after delivering a delta")
+	}
+	if len(got) != 1 || got[0] != "partial " {
+		t.Errorf("CompleteStream() deltas = %v, want only the primary's partial delta with no fallback replay", got)
+	}
+	if fallback.calls != 0 {
+		t.Errorf("fallback.calls = %d, want 0: a mid-stream failure must not fall through to another backend", fallback.calls)
+	}
+}
+
+func TestRouterCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	router := summarizer.NewRouter(nil, summarizer.NoOpLogger{})
+	backend := &countingBackend{name: "flaky", fail: true}
+	router.AddBackend(backend, summarizer.AccessConfig{})
+
+	if _, err := router.Complete(context.Background(), summarizer.Request{Prompt: "hi"}); err == nil {
+		t.Fatal("Complete() error = nil, want an error from the failing backend")
+	}
+	callsAfterFirstRequest := backend.calls
+
+	// The circuit should now be open for its cool-down window, so a second
+	// request shouldn't even attempt the backend again.
+	if _, err := router.Complete(context.Background(), summarizer.Request{Prompt: "hi"}); err == nil {
+		t.Fatal("Complete() error = nil, want an error while the circuit is open")
+	}
+	if backend.calls != callsAfterFirstRequest {
+		t.Errorf("backend.calls = %d after a second request, want unchanged (circuit should be open)", backend.calls)
+	}
+}
@@ -0,0 +1,255 @@
+// Package server exposes a summarizer.Client behind an OpenAI-compatible
+// HTTP surface, so any existing OpenAI SDK or tool (LangChain, chat UIs,
+// etc.) can point at the summarizer as if it were OpenAI.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/powerivq/summarizer"
+)
+
+// Server wraps a summarizer.Client behind /v1/chat/completions and
+// /v1/completions. Requests still flow through the Client's existing
+// Cache/LLMLogger hooks and chunking pipeline, so cached windows short-
+// circuit HTTP requests exactly as they do for direct Client callers.
+type Server struct {
+	client    *summarizer.Client
+	authToken string
+}
+
+// New returns a Server backed by client. If authToken is non-empty,
+// requests must carry a matching "Authorization: Bearer <authToken>"
+// header.
+func New(client *summarizer.Client, authToken string) *Server {
+	return &Server{client: client, authToken: authToken}
+}
+
+// Handler returns the http.Handler serving the OpenAI-compatible endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	mux.HandleFunc("/v1/completions", s.handleCompletions)
+	return mux
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if s.authToken == "" {
+		return true
+	}
+	if r.Header.Get("Authorization") == "Bearer "+s.authToken {
+		return true
+	}
+	http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+	return false
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+type chatCompletionChoice struct {
+	Index        int         `json:"index"`
+	Message      chatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type chatCompletionResponse struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []chatCompletionChoice `json:"choices"`
+}
+
+type chatCompletionChunkChoice struct {
+	Index        int         `json:"index"`
+	Delta        chatMessage `json:"delta"`
+	FinishReason string      `json:"finish_reason,omitempty"`
+}
+
+type chatCompletionChunk struct {
+	ID      string                      `json:"id"`
+	Object  string                      `json:"object"`
+	Created int64                       `json:"created"`
+	Model   string                      `json:"model"`
+	Choices []chatCompletionChunkChoice `json:"choices"`
+}
+
+// flattenMessages joins the chat messages into the single document the
+// summarizer's chunking pipeline expects, prefixing each with its role so
+// system/user/assistant turns stay distinguishable.
+func flattenMessages(messages []chatMessage) string {
+	var b strings.Builder
+	for _, m := range messages {
+		b.WriteString(m.Role)
+		b.WriteString(": ")
+		b.WriteString(m.Content)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r) {
+		return
+	}
+
+	var req chatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	text := flattenMessages(req.Messages)
+
+	if req.Stream {
+		s.streamChatCompletion(w, r.Context(), req.Model, text)
+		return
+	}
+
+	summary, err := s.client.SummarizeWith(r.Context(), text, summarizer.SummarizeOptions{Model: req.Model})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := chatCompletionResponse{
+		ID:      "chatcmpl-" + requestID(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []chatCompletionChoice{{
+			Message:      chatMessage{Role: "assistant", Content: *summary},
+			FinishReason: "stop",
+		}},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("encode chat completion response: %s", err)
+	}
+}
+
+func (s *Server) streamChatCompletion(w http.ResponseWriter, ctx context.Context, model string, text string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, err := s.client.SummarizeStreamWith(ctx, text, summarizer.SummarizeOptions{Model: model})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	id := "chatcmpl-" + requestID()
+	created := time.Now().Unix()
+	for event := range events {
+		if event.Err != nil {
+			log.Printf("summarize stream error: %s", event.Err)
+			payload, _ := json.Marshal(map[string]interface{}{
+				"error": map[string]string{"message": event.Err.Error()},
+			})
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+			return
+		}
+
+		choice := chatCompletionChunkChoice{Delta: chatMessage{Content: event.Delta}}
+		if event.Done {
+			choice.FinishReason = "stop"
+		}
+		payload, _ := json.Marshal(chatCompletionChunk{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   model,
+			Choices: []chatCompletionChunkChoice{choice},
+		})
+		fmt.Fprintf(w, "data: %s\n\n", payload)
+		flusher.Flush()
+
+		if event.Done {
+			break
+		}
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+type completionRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type completionChoice struct {
+	Index        int    `json:"index"`
+	Text         string `json:"text"`
+	FinishReason string `json:"finish_reason"`
+}
+
+type completionResponse struct {
+	ID      string             `json:"id"`
+	Object  string             `json:"object"`
+	Created int64              `json:"created"`
+	Model   string             `json:"model"`
+	Choices []completionChoice `json:"choices"`
+}
+
+func (s *Server) handleCompletions(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r) {
+		return
+	}
+
+	var req completionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Stream {
+		s.streamChatCompletion(w, r.Context(), req.Model, req.Prompt)
+		return
+	}
+
+	summary, err := s.client.SummarizeWith(r.Context(), req.Prompt, summarizer.SummarizeOptions{Model: req.Model})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := completionResponse{
+		ID:      "cmpl-" + requestID(),
+		Object:  "text_completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []completionChoice{{Text: *summary, FinishReason: "stop"}},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("encode completion response: %s", err)
+	}
+}
+
+func requestID() string {
+	return fmt.Sprintf("%x", rand.Int63())
+}
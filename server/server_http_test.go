@@ -0,0 +1,174 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkoukk/tiktoken-go"
+	"github.com/powerivq/summarizer"
+)
+
+// fakeBackend is a minimal summarizer.Backend (and StreamingBackend) whose
+// Complete/CompleteStream behavior is fully controlled by the test, so the
+// server's request handling can be exercised without a real provider.
+type fakeBackend struct {
+	mu     sync.Mutex
+	models []string
+	fail   bool
+	deltas []string
+}
+
+func (b *fakeBackend) Name() string  { return "fake" }
+func (b *fakeBackend) Healthy() bool { return true }
+
+func (b *fakeBackend) Complete(ctx context.Context, req summarizer.Request) (summarizer.Response, error) {
+	b.mu.Lock()
+	b.models = append(b.models, req.Model)
+	b.mu.Unlock()
+	return summarizer.Response{Content: "summary of: " + req.Prompt}, nil
+}
+
+func (b *fakeBackend) CompleteStream(ctx context.Context, req summarizer.Request, onDelta func(string)) error {
+	b.mu.Lock()
+	b.models = append(b.models, req.Model)
+	b.mu.Unlock()
+	if b.fail {
+		return errors.New("simulated stream failure")
+	}
+	for _, delta := range b.deltas {
+		onDelta(delta)
+	}
+	return nil
+}
+
+// newTestServer builds a Server backed by backend, skipping the test if a
+// real tiktoken encoding can't be downloaded: NewClientNoCache calls
+// tiktoken.EncodingForModel unconditionally and log.Fatals if it fails,
+// which would kill the whole test binary rather than just this test, so the
+// encoding's availability has to be checked before NewClientNoCache is
+// called at all.
+func newTestServer(t *testing.T, backend summarizer.Backend) *Server {
+	t.Helper()
+	if _, err := tiktoken.EncodingForModel("gpt-3.5-turbo"); err != nil {
+		t.Skipf("tiktoken encoding unavailable in this environment: %s", err)
+	}
+
+	client := summarizer.NewClientNoCache(summarizer.Config{})
+	client.AddBackend(backend, summarizer.AccessConfig{})
+	return New(client, "")
+}
+
+func TestAuthorizeRejectsMissingOrWrongToken(t *testing.T) {
+	s := &Server{authToken: "secret"}
+
+	for _, header := range []string{"", "Bearer wrong", "secret"} {
+		req := httptest.NewRequest("POST", "/v1/chat/completions", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		if s.authorize(rec, req) {
+			t.Errorf("authorize() with Authorization=%q = true, want false", header)
+		}
+		if rec.Code != 401 {
+			t.Errorf("authorize() with Authorization=%q status = %d, want 401", header, rec.Code)
+		}
+	}
+}
+
+func TestAuthorizeAcceptsMatchingToken(t *testing.T) {
+	s := &Server{authToken: "secret"}
+	req := httptest.NewRequest("POST", "/v1/chat/completions", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	if !s.authorize(rec, req) {
+		t.Error("authorize() with the matching token = false, want true")
+	}
+}
+
+func TestAuthorizeAcceptsAnyRequestWhenNoTokenConfigured(t *testing.T) {
+	s := &Server{}
+	req := httptest.NewRequest("POST", "/v1/chat/completions", nil)
+	rec := httptest.NewRecorder()
+	if !s.authorize(rec, req) {
+		t.Error("authorize() with no configured authToken = false, want true")
+	}
+}
+
+func TestHandleChatCompletionsNonStreamingUsesRequestedModel(t *testing.T) {
+	backend := &fakeBackend{}
+	s := newTestServer(t, backend)
+
+	body := `{"model":"test-model","messages":[{"role":"user","content":"hello"}]}`
+	req := httptest.NewRequest("POST", "/v1/chat/completions", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	s.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, want 200; body = %s", rec.Code, rec.Body.String())
+	}
+	var resp chatCompletionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %s", err)
+	}
+	if !strings.Contains(resp.Choices[0].Message.Content, "user: hello") {
+		t.Errorf("response content = %q, want it to contain the flattened request", resp.Choices[0].Message.Content)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if len(backend.models) != 1 || backend.models[0] != "test-model" {
+		t.Errorf("backend.models = %v, want [\"test-model\"]", backend.models)
+	}
+}
+
+func TestHandleChatCompletionsStreamingUsesRequestedModelAndEndsWithDone(t *testing.T) {
+	backend := &fakeBackend{deltas: []string{"Hel", "lo"}}
+	s := newTestServer(t, backend)
+
+	body := `{"model":"stream-model","messages":[{"role":"user","content":"hi"}],"stream":true}`
+	req := httptest.NewRequest("POST", "/v1/chat/completions", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	s.Handler().ServeHTTP(rec, req)
+
+	out := rec.Body.String()
+	if !strings.Contains(out, `"model":"stream-model"`) {
+		t.Errorf("stream output = %q, want a chunk carrying the requested model", out)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out), "data: [DONE]") {
+		t.Errorf("stream output = %q, want it to end with data: [DONE]", out)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if len(backend.models) != 1 || backend.models[0] != "stream-model" {
+		t.Errorf("backend.models = %v, want [\"stream-model\"]", backend.models)
+	}
+}
+
+func TestHandleChatCompletionsStreamingErrorSendsNoDone(t *testing.T) {
+	backend := &fakeBackend{fail: true}
+	s := newTestServer(t, backend)
+
+	body := `{"model":"stream-model","messages":[{"role":"user","content":"hi"}],"stream":true}`
+	req := httptest.NewRequest("POST", "/v1/chat/completions", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	s.Handler().ServeHTTP(rec, req)
+
+	out := rec.Body.String()
+	if strings.Contains(out, "[DONE]") {
+		t.Errorf("stream output = %q, a mid-stream error should not be followed by [DONE]", out)
+	}
+	if !strings.Contains(out, `"error"`) {
+		t.Errorf("stream output = %q, want an error frame reporting the failure", out)
+	}
+}
@@ -0,0 +1,20 @@
+package server
+
+import "testing"
+
+func TestFlattenMessages(t *testing.T) {
+	got := flattenMessages([]chatMessage{
+		{Role: "system", Content: "You are helpful."},
+		{Role: "user", Content: "Summarize this."},
+	})
+	want := "system: You are helpful.\nuser: Summarize this.\n"
+	if got != want {
+		t.Errorf("flattenMessages() = %q, want %q", got, want)
+	}
+}
+
+func TestFlattenMessagesEmpty(t *testing.T) {
+	if got := flattenMessages(nil); got != "" {
+		t.Errorf("flattenMessages(nil) = %q, want empty string", got)
+	}
+}
@@ -0,0 +1,39 @@
+package summarizer
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestSummarizeStreamEmptyInput exercises the one SummarizeStream path that
+// needs no backend: empty input short-circuits straight to a single Done
+// event, just like Summarize returns the empty string without calling a
+// backend. This is a white-box (internal) test, rather than one in
+// summarizer_test, because it constructs a bare *Client directly instead of
+// via NewClient, which would otherwise reach out to download a tiktoken
+// encoding.
+func TestSummarizeStreamEmptyInput(t *testing.T) {
+	client := &Client{}
+
+	events, err := client.SummarizeStream(context.Background(), "")
+	if err != nil {
+		t.Fatalf("SummarizeStream() error = %v", err)
+	}
+
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("expected a Done event, channel closed with nothing")
+		}
+		if !event.Done || event.Err != nil {
+			t.Errorf("SummarizeStream(\"\") event = %+v, want Done with no error", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Done event")
+	}
+
+	if _, ok := <-events; ok {
+		t.Error("expected channel to be closed after the Done event")
+	}
+}
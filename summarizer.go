@@ -1,6 +1,7 @@
 package summarizer
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,13 +9,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
-	"math/rand"
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
-	"unicode/utf8"
 
 	"github.com/pkoukk/tiktoken-go"
 	openai "github.com/sashabaranov/go-openai"
@@ -24,12 +24,11 @@ var chineseMatcher = regexp.MustCompile("[\u4e00-\u9fa5]")
 var englishMatcher = regexp.MustCompile(`[a-zA-Z]`)
 
 type Client struct {
-	azureClients  []openai.Client
-	openaiClients []openai.Client
-	gcpTokens     []string
-	tiktoken      tiktoken.Tiktoken
-	cache         Cache
-	logger        LLMLogger
+	gcpTokens []string
+	tiktoken  tiktoken.Tiktoken
+	cache     Cache
+	logger    LLMLogger
+	router    *Router
 }
 
 type NoCache struct{}
@@ -46,19 +45,21 @@ func NewClientNoCache(config Config) *Client {
 }
 
 func NewClient(config Config, cache Cache, logger LLMLogger) *Client {
-	var azureClients []openai.Client
-	var openaiClients []openai.Client
 	var gcpTokens []string
-	for _, config := range config.AccessConfigs {
-		if config.APIType == APITypeOpenAI {
-			openaiClients = append(openaiClients, *openai.NewClient(config.AuthToken))
+	var routerEntries []*routerEntry
+	for _, accessConfig := range config.AccessConfigs {
+		if accessConfig.APIType == APITypeOpenAI {
+			client := *openai.NewClient(accessConfig.AuthToken)
+			routerEntries = append(routerEntries, newRouterEntry(NewOpenAIBackend(client), accessConfig))
 		}
-		if config.APIType == APITypeAzure {
-			clientConfig := openai.DefaultAzureConfig(config.AuthToken, config.BaseURL)
-			azureClients = append(azureClients, *openai.NewClientWithConfig(clientConfig))
+		if accessConfig.APIType == APITypeAzure {
+			clientConfig := openai.DefaultAzureConfig(accessConfig.AuthToken, accessConfig.BaseURL)
+			client := *openai.NewClientWithConfig(clientConfig)
+			routerEntries = append(routerEntries, newRouterEntry(NewAzureBackend(client), accessConfig))
 		}
-		if config.APIType == APITypeGCPGemini {
-			gcpTokens = append(gcpTokens, config.AuthToken)
+		if accessConfig.APIType == APITypeGCPGemini {
+			gcpTokens = append(gcpTokens, accessConfig.AuthToken)
+			routerEntries = append(routerEntries, newRouterEntry(NewGeminiBackend(accessConfig.AuthToken), accessConfig))
 		}
 	}
 
@@ -67,155 +68,399 @@ func NewClient(config Config, cache Cache, logger LLMLogger) *Client {
 		log.Fatal("Tiktoken failed to load: ", err)
 	}
 	return &Client{
-		azureClients:  azureClients,
-		openaiClients: openaiClients,
-		gcpTokens:     gcpTokens,
-		tiktoken:      *tiktoken,
-		cache:         cache,
-		logger:        logger,
+		gcpTokens: gcpTokens,
+		tiktoken:  *tiktoken,
+		cache:     cache,
+		logger:    logger,
+		router:    NewRouter(routerEntries, logger),
 	}
 }
 
+// Router exposes the Client's backend Router so callers can observe routing
+// decisions (Router.OnRoute) or inspect backend health.
+func (c *Client) Router() *Router {
+	return c.router
+}
+
+// AddBackend registers a custom Backend with the Client's Router, so callers
+// can route to providers beyond the Azure/OpenAI/Gemini set NewClient builds
+// from Config. cfg's Weight/Priority (or their defaults) govern how the
+// backend is prioritized against the rest.
+func (c *Client) AddBackend(backend Backend, cfg AccessConfig) {
+	c.router.AddBackend(backend, cfg)
+}
+
+// Summarize writes a case brief for text using the backend-default model
+// and sampling. It is equivalent to SummarizeWith with the zero-value
+// SummarizeOptions.
 func (c *Client) Summarize(text string) (*string, error) {
+	return c.summarize(context.Background(), text, defaultSummarizeOptions())
+}
+
+// SummarizeWith is the general entry point: it builds its prompt from
+// opts.Strategy (CaseBriefStrategy if unset), drives the backend with
+// opts.Model/Temperature/MaxOutputTokens, and windows long input according
+// to opts.Chain, so callers outside the legal-judgment use case can
+// summarize without forking the module. ctx cancellation is honored by
+// every chain, including the ChainMapReduce worker pool.
+func (c *Client) SummarizeWith(ctx context.Context, text string, opts SummarizeOptions) (*string, error) {
+	if opts.Strategy == nil {
+		opts.Strategy = CaseBriefStrategy{}
+	}
+	return c.summarize(ctx, text, opts)
+}
+
+func (c *Client) summarize(ctx context.Context, text string, opts SummarizeOptions) (*string, error) {
 	text = PruneInvisibleCharacters(text)
 	if len(text) == 0 {
 		log.Printf("Empty input, ignoring")
 		return &text, nil
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	log.Printf("Summarizing %d bytes", len(text))
 
-	chineseChars := len(chineseMatcher.FindAllString(text, -1))
-	englishChars := len(englishMatcher.FindAllString(text, -1))
-
-	var prompt string
-	if chineseChars >= englishChars {
-		prompt = "In Chinese, write a case brief for the following judgment, includes the facts, procedural history, holdings, rationales for each holding, and final disposition: \n\n"
-	} else {
-		prompt = "Write a case brief for the following judgment, includes the facts, procedural history, holdings, rationales for each holding, and final disposition: \n\n"
-	}
+	prompt := opts.Strategy.Build(text, opts.Language)
 
-	if tokens := len(c.tiktoken.Encode(text, nil, nil)); tokens <= 25000 {
-		content, err := c.requestGpt(prompt+text)
+	// splitIntoWindows (via Chunker) also decides whether windowing is
+	// needed at all, counting the text with opts.Model's own token counter
+	// instead of the Client's fixed gpt-3.5-turbo tiktoken encoding, so a single
+	// fitting window (or no split at all) takes the same single-pass path a
+	// short input would.
+	windows := c.splitIntoWindows(text, prompt, opts)
+	if len(windows) <= 1 {
+		content, err := c.requestGpt(ctx, prompt+text, opts)
 		if err != nil {
 			return nil, err
 		}
 		return content, nil
 	}
 
-	texts := strings.Split(text, "\n")
+	switch opts.Chain {
+	case ChainMapReduce:
+		return c.summarizeMapReduce(ctx, windows, opts)
+	case ChainRefine:
+		return c.summarizeRefine(ctx, windows, prompt, opts)
+	default:
+		return c.summarizeRecursive(ctx, windows, opts)
+	}
+}
 
+// summarizeRecursive is the original chain: summarize each window in turn,
+// caching completed windows, then recurse on the concatenated partials
+// until they fit in a single pass.
+func (c *Client) summarizeRecursive(ctx context.Context, windows []string, opts SummarizeOptions) (*string, error) {
 	var summary strings.Builder
-	var window strings.Builder
-	var tokens int
-
-	promptTokens := len(c.tiktoken.Encode(prompt, nil, nil))
-	for i := 0; i < len(texts); {
-		window.Reset()
-		window.WriteString(prompt)
-		tokens = promptTokens
-
-		for ; i < len(texts) && tokens <= 24000; i++ {
-			window.WriteString(texts[i])
-			window.WriteString("\n")
-			tokens += len(c.tiktoken.Encode(texts[i], nil, nil)) + 1
-		}
-		for ; i < len(texts) && !strings.ContainsAny(texts[i-1], ".?!。！") && strings.TrimSpace(texts[i-1]) != ""; i++ {
-			window.WriteString(texts[i])
-			window.WriteString("\n")
-			tokens += len(c.tiktoken.Encode(texts[i], nil, nil)) + 1
-		}
-		lastChar, size := utf8.DecodeLastRuneInString(strings.TrimSpace(texts[i-1]))
-		if i != len(texts) && size == 1 && lastChar != '.' && lastChar != '?' && lastChar != '!' && lastChar != '。' && lastChar != '！' {
-			i--
-		}
-
-		log.Printf("GPTing for %d tokens", tokens)
-		prompt := window.String()
+	for _, windowPrompt := range windows {
+		log.Printf("GPTing for %d bytes", len(windowPrompt))
 
-		if cacheResults := c.cache.Get("gpt:" + GetMD5Hash(prompt)); cacheResults != nil {
-			log.Printf("Partial result (cached): %d bytes => %d bytes", len(prompt), len(*cacheResults))
+		if cacheResults := c.cache.Get("gpt:" + GetMD5Hash(windowPrompt)); cacheResults != nil {
+			log.Printf("Partial result (cached): %d bytes => %d bytes", len(windowPrompt), len(*cacheResults))
 			summary.WriteString(*cacheResults)
 			summary.WriteString("\n")
 		} else {
-			content, err := c.requestGpt(prompt)
+			content, err := c.requestGpt(ctx, windowPrompt, opts)
 			if err != nil {
 				log.Printf("openai error: %s", err)
 				return nil, err
 			}
 
-			log.Printf("Partial result: %d bytes => %d bytes", len(prompt), len(*content))
-			c.cache.Set("gpt:"+GetMD5Hash(prompt), *content)
+			log.Printf("Partial result: %d bytes => %d bytes", len(windowPrompt), len(*content))
+			c.cache.Set("gpt:"+GetMD5Hash(windowPrompt), *content)
 			summary.WriteString(*content)
 			summary.WriteString("\n")
 		}
 	}
-	return c.Summarize(summary.String())
+	return c.summarize(ctx, summary.String(), opts)
 }
 
-func (c *Client) requestGpt(prompt string) (*string, error) {
-	invalidInput := false
-	if len(c.azureClients) > 0 && !invalidInput {
-		for i := 0; i < 3; i++ {
-			client := &c.azureClients[rand.Intn(len(c.azureClients))]
-			res, err := c.doRequestGpt(client, prompt)
-			if err == nil {
-				c.logger.Log(prompt, *res, APITypeAzure)
-				return res, nil
+// summarizeMapReduce summarizes every window concurrently (bounded by
+// opts.Concurrency), then reduces the concatenated partials through the
+// default recursive chain. It fails fast on the first non-retryable error
+// (cancelling in-flight windows) but still caches every window that
+// completed before that happened.
+func (c *Client) summarizeMapReduce(ctx context.Context, windows []string, opts SummarizeOptions) (*string, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]string, len(windows))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var firstErr error
+	var mu sync.Mutex
+
+	for i, windowPrompt := range windows {
+		i, windowPrompt := i, windowPrompt
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
 			}
-			apiError := &openai.APIError{}
-			if errors.As(err, &apiError) && apiError.HTTPStatusCode == 400 {
-				invalidInput = true
+
+			log.Printf("GPTing for %d bytes", len(windowPrompt))
+			if cacheResults := c.cache.Get("gpt:" + GetMD5Hash(windowPrompt)); cacheResults != nil {
+				log.Printf("Partial result (cached): %d bytes => %d bytes", len(windowPrompt), len(*cacheResults))
+				results[i] = *cacheResults
+				return
 			}
-			log.Printf("GPT error: %s", err)
-		}
-	}
-	if len(c.openaiClients) > 0 {
-		for i := 0; i < 3; i++ {
-			client := &c.openaiClients[rand.Intn(len(c.openaiClients))]
-			res, err := c.doRequestGpt(client, prompt)
-			if err == nil {
-				c.logger.Log(prompt, *res, APITypeOpenAI)
-				return res, nil
+
+			content, err := c.requestGpt(ctx, windowPrompt, opts)
+			if err != nil {
+				log.Printf("openai error: %s", err)
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+				return
 			}
-			log.Printf("GPT error: %s", err)
+
+			log.Printf("Partial result: %d bytes => %d bytes", len(windowPrompt), len(*content))
+			c.cache.Set("gpt:"+GetMD5Hash(windowPrompt), *content)
+			results[i] = *content
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var summary strings.Builder
+	for _, result := range results {
+		summary.WriteString(result)
+		summary.WriteString("\n")
+	}
+
+	reduceOpts := opts
+	reduceOpts.Chain = ChainRecursive
+	return c.summarize(ctx, summary.String(), reduceOpts)
+}
+
+// summarizeRefine walks windows sequentially, feeding the running summary
+// and the next window's raw text into a refine prompt so context
+// accumulates without ever needing a final reduce pass.
+func (c *Client) summarizeRefine(ctx context.Context, windows []string, prompt string, opts SummarizeOptions) (*string, error) {
+	var runningSummary string
+	for _, windowPrompt := range windows {
+		chunk := strings.TrimPrefix(windowPrompt, prompt)
+		refinePrompt := buildRefinePrompt(prompt, runningSummary, chunk)
+		log.Printf("Refining with %d bytes", len(refinePrompt))
+
+		if cacheResults := c.cache.Get("gpt:" + GetMD5Hash(refinePrompt)); cacheResults != nil {
+			log.Printf("Refine result (cached): %d bytes => %d bytes", len(refinePrompt), len(*cacheResults))
+			runningSummary = *cacheResults
+			continue
+		}
+
+		content, err := c.requestGpt(ctx, refinePrompt, opts)
+		if err != nil {
+			log.Printf("openai error: %s", err)
+			return nil, err
 		}
+
+		log.Printf("Refine result: %d bytes => %d bytes", len(refinePrompt), len(*content))
+		c.cache.Set("gpt:"+GetMD5Hash(refinePrompt), *content)
+		runningSummary = *content
+	}
+	return &runningSummary, nil
+}
+
+// buildRefinePrompt builds the instruction for one refine step. The first
+// window has no running summary yet, so it gets the plain base prompt;
+// later windows are asked to integrate new material into what's already
+// been written.
+func buildRefinePrompt(basePrompt string, runningSummary string, chunk string) string {
+	if runningSummary == "" {
+		return basePrompt + chunk
 	}
-	if len(c.gcpTokens) > 0 {
-		for i := 0; i < 3; i++ {
-			token := c.gcpTokens[rand.Intn(len(c.gcpTokens))]
-			res, err := c.doRequestGemini(token, prompt)
-			if err == nil {
-				c.logger.Log(prompt, *res, APITypeGCPGemini)
-				return res, nil
+	return "Here is the summary so far:\n\n" + runningSummary +
+		"\n\nRefine it using the following additional material, preserving everything already covered: \n\n" + chunk
+}
+
+// splitIntoWindows breaks text into prompt-prefixed windows via Chunker,
+// counting the text with opts.Model's own token encoding and preferring
+// paragraph, then sentence, then word boundaries over the single-rune
+// sentence heuristic this used to inline directly. The tokens reserved for
+// prompt are counted with the Client's gpt-3.5-turbo encoding, whatever
+// opts.Model is.
+func (c *Client) splitIntoWindows(text string, prompt string, opts SummarizeOptions) []string {
+	promptTokens := len(c.tiktoken.Encode(prompt, nil, nil))
+	chunks := c.Chunk(text, ChunkOptions{Model: opts.Model, PromptTokens: promptTokens})
+
+	windows := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		windows[i] = prompt + chunk.Text
+	}
+	return windows
+}
+
+// SummaryEvent is a single increment of a streamed summary. Delta carries the
+// newly produced text, ChunkIndex identifies which window (0 for the first
+// pass over the source text) produced it, and Provider names the backend
+// that actually served it. The stream ends with either a Done event or an
+// Err event; callers should stop reading from the channel after either.
+type SummaryEvent struct {
+	Delta      string
+	ChunkIndex int
+	Provider   APIType
+	Done       bool
+	Err        error
+}
+
+// SummarizeStream streams a case brief for text using the backend-default
+// model and sampling. It is equivalent to SummarizeStreamWith with the
+// zero-value SummarizeOptions.
+func (c *Client) SummarizeStream(ctx context.Context, text string) (<-chan SummaryEvent, error) {
+	return c.SummarizeStreamWith(ctx, text, defaultSummarizeOptions())
+}
+
+// SummarizeStreamWith mirrors SummarizeWith but delivers the completion
+// incrementally as it is generated instead of waiting for the full
+// response. Intermediate windows of the recursive/windowed path are still
+// summarized and cached up front the way SummarizeWith does today; only the
+// final pass over the combined partials is streamed back to the caller.
+// opts.Model/Temperature/MaxOutputTokens drive every backend call the
+// stream makes, including that final pass. opts.Chain is not consulted:
+// long input always takes the ChainRecursive path.
+func (c *Client) SummarizeStreamWith(ctx context.Context, text string, opts SummarizeOptions) (<-chan SummaryEvent, error) {
+	if opts.Strategy == nil {
+		opts.Strategy = CaseBriefStrategy{}
+	}
+	text = PruneInvisibleCharacters(text)
+	ch := make(chan SummaryEvent)
+	if len(text) == 0 {
+		log.Printf("Empty input, ignoring")
+		go func() {
+			defer close(ch)
+			ch <- SummaryEvent{Done: true}
+		}()
+		return ch, nil
+	}
+
+	log.Printf("Streaming summary for %d bytes", len(text))
+	prompt := opts.Strategy.Build(text, opts.Language)
+
+	windows := c.splitIntoWindows(text, prompt, opts)
+	if len(windows) <= 1 {
+		go c.streamPrompt(ctx, prompt+text, 0, opts, ch)
+		return ch, nil
+	}
+
+	go c.streamWindowed(ctx, text, prompt, opts, ch)
+	return ch, nil
+}
+
+// streamWindowed summarizes each window of text non-streamed (caching
+// completed windows as SummarizeWith does), then streams the final pass
+// over the concatenated partials.
+func (c *Client) streamWindowed(ctx context.Context, text string, prompt string, opts SummarizeOptions, ch chan<- SummaryEvent) {
+	defer close(ch)
+
+	windows := c.splitIntoWindows(text, prompt, opts)
+	var summary strings.Builder
+	for chunkIndex, windowPrompt := range windows {
+		log.Printf("GPTing for %d bytes", len(windowPrompt))
+
+		if cacheResults := c.cache.Get("gpt:" + GetMD5Hash(windowPrompt)); cacheResults != nil {
+			log.Printf("Partial result (cached): %d bytes => %d bytes", len(windowPrompt), len(*cacheResults))
+			summary.WriteString(*cacheResults)
+			summary.WriteString("\n")
+		} else {
+			content, err := c.requestGpt(ctx, windowPrompt, opts)
+			if err != nil {
+				log.Printf("openai error: %s", err)
+				ch <- SummaryEvent{Err: err, ChunkIndex: chunkIndex}
+				return
 			}
-			log.Printf("Gemini error: %s", err)
+
+			log.Printf("Partial result: %d bytes => %d bytes", len(windowPrompt), len(*content))
+			c.cache.Set("gpt:"+GetMD5Hash(windowPrompt), *content)
+			summary.WriteString(*content)
+			summary.WriteString("\n")
 		}
 	}
-	return nil, errors.New("all retries have failed")
+
+	finalCh, err := c.SummarizeStreamWith(ctx, summary.String(), opts)
+	if err != nil {
+		ch <- SummaryEvent{Err: err, ChunkIndex: len(windows)}
+		return
+	}
+	for event := range finalCh {
+		event.ChunkIndex += len(windows)
+		ch <- event
+	}
 }
 
-func (c *Client) doRequestGpt(client *openai.Client, prompt string) (*string, error) {
-	resp, err := client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo16K,
-			Messages: []openai.ChatCompletionMessage{{
-				Role:    openai.ChatMessageRoleUser,
-				Content: prompt,
-			}},
-			Stream:      false,
-		},
-	)
+// streamPrompt routes prompt through the Client's Router the same way
+// requestGpt does, so circuit breaker state, weighted/priority routing, and
+// any custom Backend registered via AddBackend are honored by the streaming
+// path too, and streams the completion instead of waiting for it in full.
+func (c *Client) streamPrompt(ctx context.Context, prompt string, chunkIndex int, opts SummarizeOptions, ch chan<- SummaryEvent) {
+	defer close(ch)
+
+	req := Request{
+		Prompt:          prompt,
+		Model:           opts.Model,
+		Temperature:     opts.Temperature,
+		MaxOutputTokens: opts.MaxOutputTokens,
+	}
+
+	var provider APIType
+	err := c.router.CompleteStream(ctx, req, func(p APIType, delta string) {
+		provider = p
+		ch <- SummaryEvent{Delta: delta, ChunkIndex: chunkIndex, Provider: p}
+	})
+	if err != nil {
+		ch <- SummaryEvent{Err: err, ChunkIndex: chunkIndex}
+		return
+	}
+	ch <- SummaryEvent{ChunkIndex: chunkIndex, Provider: provider, Done: true}
+}
+
+// requestGpt routes prompt through the Client's Router, which picks a
+// healthy backend (Azure, OpenAI, or Gemini, in priority order) and retries
+// on failure the way the fixed Azure->OpenAI->Gemini chain used to.
+func (c *Client) requestGpt(ctx context.Context, prompt string, opts SummarizeOptions) (*string, error) {
+	resp, err := c.router.Complete(ctx, Request{
+		Prompt:          prompt,
+		Model:           opts.Model,
+		Temperature:     opts.Temperature,
+		MaxOutputTokens: opts.MaxOutputTokens,
+	})
 	if err != nil {
-		log.Printf("openai error: %s", err)
 		return nil, err
 	}
+	return &resp.Content, nil
+}
 
-	return &resp.Choices[0].Message.Content, nil
+// doRequestGeminiWithContext calls Gemini's generateContent endpoint with a
+// single text part. It is a free function, rather than a Client method, so
+// GeminiBackend can call it without holding a Client.
+func doRequestGeminiWithContext(ctx context.Context, token string, prompt string, opts SummarizeOptions) (*string, error) {
+	return doRequestGeminiPartsWithContext(ctx, token, []GeminiRequestContentsMessagePart{{Text: prompt}}, opts)
 }
 
-func (c *Client) doRequestGemini(token string, prompt string) (*string, error) {
+// doRequestGeminiPartsWithContext calls Gemini's generateContent endpoint
+// with an arbitrary set of message parts, so callers that attach images or
+// other files (via inline_data/file_data) can share the same HTTP plumbing
+// as the plain text path.
+func doRequestGeminiPartsWithContext(ctx context.Context, token string, parts []GeminiRequestContentsMessagePart, opts SummarizeOptions) (*string, error) {
 	netTransport := &http.Transport{
 		TLSHandshakeTimeout: 10 * time.Second,
 	}
@@ -225,50 +470,20 @@ func (c *Client) doRequestGemini(token string, prompt string) (*string, error) {
 		Transport: netTransport,
 	}
 
-	requestJson := GeminiRequest{
-		Messages: []GeminiRequestContentsMessage{
-			GeminiRequestContentsMessage{
-				Role: "user",
-				Parts: []GeminiRequestContentsMessagePart{
-					GeminiRequestContentsMessagePart{Text: prompt},
-				},
-			},
-		},
-		Config: GeminiRequestGenerationConfig{
-			Temperature:     0.9,
-			TopK:            1,
-			TopP:            1,
-			MaxOutputTokens: 2048,
-			StopSequences:   []string{},
-		},
-		SafetySettings: []map[string]interface{}{
-			{
-				"category":  "HARM_CATEGORY_HARASSMENT",
-				"threshold": "BLOCK_NONE",
-			},
-			{
-				"category":  "HARM_CATEGORY_HATE_SPEECH",
-				"threshold": "BLOCK_NONE",
-			},
-			{
-				"category":  "HARM_CATEGORY_SEXUALLY_EXPLICIT",
-				"threshold": "BLOCK_NONE",
-			},
-			{
-				"category":  "HARM_CATEGORY_DANGEROUS_CONTENT",
-				"threshold": "BLOCK_NONE",
-			},
-		},
-	}
+	requestJson := geminiRequestForParts(parts, opts)
 	payload, err := json.Marshal(requestJson)
 	if err != nil {
 		return nil, fmt.Errorf("Gemini serialization failure: %s", err)
 	}
 
-	request, _ := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
-		"https://generativelanguage.googleapis.com/v1beta/models/gemini-1.0-pro:generateContent?key="+token,
+		"https://generativelanguage.googleapis.com/v1beta/models/"+geminiModel(opts)+":generateContent?key="+token,
 		bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("Gemini request failure: %s", err)
+	}
 	request.Header.Add("content-type", "application/json")
 	response, err := client.Do(request)
 	if err != nil || response == nil {
@@ -297,6 +512,141 @@ func (c *Client) doRequestGemini(token string, prompt string) (*string, error) {
 	return &geminiResult, nil
 }
 
+// doRequestGeminiStreamWithContext streams a Gemini completion via
+// streamGenerateContent SSE, calling onDelta with each non-empty text delta
+// as it arrives. It is a free function, rather than a Client method, so
+// GeminiBackend can call it without holding a Client.
+func doRequestGeminiStreamWithContext(ctx context.Context, token string, prompt string, opts SummarizeOptions, onDelta func(string)) error {
+	netTransport := &http.Transport{
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+
+	client := &http.Client{
+		Timeout:   120 * time.Second,
+		Transport: netTransport,
+	}
+
+	requestJson := geminiRequestForPrompt(prompt, opts)
+	payload, err := json.Marshal(requestJson)
+	if err != nil {
+		return fmt.Errorf("Gemini serialization failure: %s", err)
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		"https://generativelanguage.googleapis.com/v1beta/models/"+geminiModel(opts)+":streamGenerateContent?alt=sse&key="+token,
+		bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("Gemini request failure: %s", err)
+	}
+	request.Header.Add("content-type", "application/json")
+	response, err := client.Do(request)
+	if err != nil || response == nil {
+		return fmt.Errorf("Gemini failure: %s", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf(
+			"Gemini status: %d\nresponse: %s", response.StatusCode, string(body))
+	}
+
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(scanner.Text(), "data: ")
+		if line == "" {
+			continue
+		}
+
+		var chunk GeminiResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return fmt.Errorf("Gemini parse response: %s", err)
+		}
+		if len(chunk.Candidates) == 0 {
+			continue
+		}
+
+		delta := concatenateStrings(chunk.Candidates[0].Content.Parts)
+		if delta == "" {
+			continue
+		}
+		onDelta(delta)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Gemini read response: %s", err)
+	}
+	return nil
+}
+
+// geminiModel returns opts.Model if set, otherwise the default Gemini model
+// Summarize has always talked to.
+func geminiModel(opts SummarizeOptions) string {
+	if opts.Model != "" {
+		return opts.Model
+	}
+	return "gemini-1.0-pro"
+}
+
+// geminiRequestForPrompt builds the single-turn, single-text-part Gemini
+// request body. It is a thin wrapper over geminiRequestForParts for the
+// common case where the message has no attachments.
+func geminiRequestForPrompt(prompt string, opts SummarizeOptions) GeminiRequest {
+	return geminiRequestForParts([]GeminiRequestContentsMessagePart{{Text: prompt}}, opts)
+}
+
+// geminiRequestForParts builds the single-turn user message Gemini request
+// body shared by the plain, streaming, and multimodal completion calls,
+// applying opts.Temperature/MaxOutputTokens over the defaults when set. An
+// explicit Temperature of 0 is honored as-is: GeminiRequestGenerationConfig's
+// Temperature field has no `omitempty`, so unlike the OpenAI/Azure path it
+// needs no epsilon substitution to be sent literally.
+func geminiRequestForParts(parts []GeminiRequestContentsMessagePart, opts SummarizeOptions) GeminiRequest {
+	temperature := float32(0.9)
+	if opts.Temperature != nil {
+		temperature = *opts.Temperature
+	}
+	maxOutputTokens := 2048
+	if opts.MaxOutputTokens != 0 {
+		maxOutputTokens = opts.MaxOutputTokens
+	}
+	return GeminiRequest{
+		Messages: []GeminiRequestContentsMessage{
+			GeminiRequestContentsMessage{
+				Role:  "user",
+				Parts: parts,
+			},
+		},
+		Config: GeminiRequestGenerationConfig{
+			Temperature:     temperature,
+			TopK:            1,
+			TopP:            1,
+			MaxOutputTokens: maxOutputTokens,
+			StopSequences:   []string{},
+		},
+		SafetySettings: []map[string]interface{}{
+			{
+				"category":  "HARM_CATEGORY_HARASSMENT",
+				"threshold": "BLOCK_NONE",
+			},
+			{
+				"category":  "HARM_CATEGORY_HATE_SPEECH",
+				"threshold": "BLOCK_NONE",
+			},
+			{
+				"category":  "HARM_CATEGORY_SEXUALLY_EXPLICIT",
+				"threshold": "BLOCK_NONE",
+			},
+			{
+				"category":  "HARM_CATEGORY_DANGEROUS_CONTENT",
+				"threshold": "BLOCK_NONE",
+			},
+		},
+	}
+}
+
 func concatenateStrings(parts []GeminiResponseCandidateContentPart) string {
 	var result string
 	for _, part := range parts {
@@ -0,0 +1,33 @@
+package summarizer
+
+import "testing"
+
+func TestChatCompletionRequestExplicitZeroTemperature(t *testing.T) {
+	zero := Float32Ptr(0)
+	req := chatCompletionRequest(Request{Prompt: "hi", Temperature: zero})
+	if req.Temperature != openAIZeroTemperatureEpsilon {
+		t.Errorf("chatCompletionRequest() Temperature = %v, want epsilon %v for explicit 0", req.Temperature, openAIZeroTemperatureEpsilon)
+	}
+}
+
+func TestChatCompletionRequestUnsetTemperature(t *testing.T) {
+	req := chatCompletionRequest(Request{Prompt: "hi"})
+	if req.Temperature != 0 {
+		t.Errorf("chatCompletionRequest() Temperature = %v, want 0 (unset, provider default applies)", req.Temperature)
+	}
+}
+
+func TestGeminiRequestForPartsExplicitZeroTemperature(t *testing.T) {
+	zero := Float32Ptr(0)
+	req := geminiRequestForParts(nil, SummarizeOptions{Temperature: zero})
+	if req.Config.Temperature != 0 {
+		t.Errorf("geminiRequestForParts() Temperature = %v, want 0 for explicit 0", req.Config.Temperature)
+	}
+}
+
+func TestGeminiRequestForPartsUnsetTemperatureDefaults(t *testing.T) {
+	req := geminiRequestForParts(nil, SummarizeOptions{})
+	if req.Config.Temperature != 0.9 {
+		t.Errorf("geminiRequestForParts() Temperature = %v, want 0.9 default when unset", req.Config.Temperature)
+	}
+}
@@ -0,0 +1,17 @@
+package summarizer
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+)
+
+func GetMD5Hash(text string) string {
+	hash := md5.Sum([]byte(text))
+	return hex.EncodeToString(hash[:])
+}
+
+func GetSHA1Hash(data []byte) string {
+	hash := sha1.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
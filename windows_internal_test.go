@@ -0,0 +1,85 @@
+package summarizer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// TestSplitIntoWindowsUsesChunker guards against splitIntoWindows regressing
+// back to an inline ad-hoc splitter: it should produce the same
+// sentence-boundary-safe windows Chunker.Chunk does, just with the prompt
+// prepended to each one. It needs a real tiktoken encoding (network access
+// on first run to fetch the BPE ranks), so it skips rather than fails in
+// offline environments.
+func TestSplitIntoWindowsUsesChunker(t *testing.T) {
+	tk, err := tiktoken.EncodingForModel("gpt-3.5-turbo")
+	if err != nil {
+		t.Skipf("tiktoken encoding unavailable in this environment: %s", err)
+	}
+	client := &Client{tiktoken: *tk}
+
+	prompt := "Summarize: "
+	text := "Dr. Smith filed the motion. " + strings.Repeat("Additional context follows here. ", 10000)
+
+	windows := client.splitIntoWindows(text, prompt, SummarizeOptions{})
+	if len(windows) < 2 {
+		t.Fatalf("expected long input to split into multiple windows, got %d", len(windows))
+	}
+	for i, window := range windows {
+		if !strings.HasPrefix(window, prompt) {
+			t.Errorf("window %d = %q, want it prefixed with the prompt", i, window)
+		}
+		if strings.Contains(window, "Dr. Smith") && !strings.Contains(window, "Dr. Smith filed the motion.") {
+			t.Errorf("window %d split inside the \"Dr.\" abbreviation: %q", i, window)
+		}
+	}
+}
+
+// gateCountingBackend is a minimal Backend fake that counts how many times
+// Complete is called, so tests can tell a single-pass call from a windowed
+// chain without a real provider.
+type gateCountingBackend struct {
+	calls int
+}
+
+func (b *gateCountingBackend) Name() string  { return "fake" }
+func (b *gateCountingBackend) Healthy() bool { return true }
+func (b *gateCountingBackend) Complete(ctx context.Context, req Request) (Response, error) {
+	b.calls++
+	return Response{Content: "summary"}, nil
+}
+
+// TestSummarizeSinglePassGateUsesModelAwareTokenCount guards against the
+// single-pass-vs-windowed decision in summarize() reverting to the old
+// fixed gpt-3.5-turbo/25000-token gate. CJK text packs far more tiktoken
+// BPE tokens per rune than the Gemini fallback estimator's one-token-per-
+// rune count, so this text sits under budget for opts.Model "gemini-1.5-
+// pro" but would have tripped the old hardcoded gate into windowing (and
+// with ChainMapReduce/ChainRefine, skipped those chains entirely either
+// way). A correct, model-aware gate makes exactly one backend call.
+func TestSummarizeSinglePassGateUsesModelAwareTokenCount(t *testing.T) {
+	tk, err := tiktoken.EncodingForModel("gpt-3.5-turbo")
+	if err != nil {
+		t.Skipf("tiktoken encoding unavailable in this environment: %s", err)
+	}
+
+	text := strings.Repeat("测试文本用于验证令牌计数器。", 1500)
+	if tokens := len(tk.Encode(text, nil, nil)); tokens <= 25000 {
+		t.Fatalf("test fixture assumption broken: gpt-3.5-turbo now encodes the fixture to %d tokens, want > 25000", tokens)
+	}
+
+	backend := &gateCountingBackend{}
+	client := &Client{tiktoken: *tk, cache: NoCache{}, logger: NoOpLogger{}}
+	client.router = NewRouter(nil, NoOpLogger{})
+	client.router.AddBackend(backend, AccessConfig{})
+
+	if _, err := client.SummarizeWith(context.Background(), text, SummarizeOptions{Model: "gemini-1.5-pro"}); err != nil {
+		t.Fatalf("SummarizeWith() error = %v", err)
+	}
+	if backend.calls != 1 {
+		t.Errorf("backend.calls = %d, want 1 (single pass, driven by opts.Model's own token count)", backend.calls)
+	}
+}